@@ -0,0 +1,100 @@
+package expr
+
+import (
+	"fmt"
+
+	"github.com/levi/holo/token"
+)
+
+// argumentError reports a native called with an argument of the wrong type
+func argumentError(paren token.Token, signature *Signature, index int, expected string) error {
+	return NewRuntimeError(paren, fmt.Sprintf("Argument '%s' of %s() must be %s.", signature.Params[index], signature.Name, expected))
+}
+
+func stringArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (string, error) {
+	s, ok := arguments[index].(string)
+	if !ok {
+		return "", argumentError(paren, signature, index, "a string")
+	}
+	return s, nil
+}
+
+// intArgument expects an integer which fits in an int64
+func intArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (int64, error) {
+	n, ok := arguments[index].(int64)
+	if !ok {
+		return 0, argumentError(paren, signature, index, "an integer")
+	}
+	return n, nil
+}
+
+func listArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (*List, error) {
+	l, ok := arguments[index].(*List)
+	if !ok {
+		return nil, argumentError(paren, signature, index, "a list")
+	}
+	return l, nil
+}
+
+func numberArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (interface{}, error) {
+	if !isNumber(arguments[index]) {
+		return nil, argumentError(paren, signature, index, "a number")
+	}
+	return arguments[index], nil
+}
+
+func callableArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (Callable, error) {
+	c, ok := arguments[index].(Callable)
+	if !ok {
+		return nil, argumentError(paren, signature, index, "a function")
+	}
+	return c, nil
+}
+
+func mapArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (*Map, error) {
+	m, ok := arguments[index].(*Map)
+	if !ok {
+		return nil, argumentError(paren, signature, index, "a map")
+	}
+	return m, nil
+}
+
+// iterableArgument begins iterating over anything for ... in can iterate, so builtins can consume
+// elements one at a time rather than holding a whole range or iterator in memory. A list is iterated
+// over a copy of its elements when passed, so callbacks changing it don't change the iteration.
+func iterableArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (Iterator, error) {
+	if list, ok := arguments[index].(*List); ok {
+		return &listIterator{list: NewList(append([]interface{}(nil), list.Elements...))}, nil
+	}
+	iterator, err := iterate(paren, arguments[index], false)
+	if err != nil {
+		// An iterable failing to start reports its own error
+		if _, ok := arguments[index].(Iterable); ok {
+			return nil, err
+		}
+		return nil, argumentError(paren, signature, index, "iterable")
+	}
+	return iterator, nil
+}
+
+// elementsArgument collects the elements of an iterable argument for builtins which need them all at once
+func elementsArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) ([]interface{}, error) {
+	if list, ok := arguments[index].(*List); ok {
+		return list.Elements, nil
+	}
+	iterator, err := iterableArgument(paren, signature, arguments, index)
+	if err != nil {
+		return nil, err
+	}
+	elements := []interface{}{}
+	for {
+		element, ok, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return elements, nil
+		}
+		elements = append(elements, element)
+	}
+}
@@ -0,0 +1,76 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+// compoundOperators maps compound assignment operators to the binary operator they apply
+var compoundOperators = map[string]string{
+	token.PlusEqualToken:    token.PlusToken,
+	token.MinusEqualToken:   token.MinusToken,
+	token.StarEqualToken:    token.StarToken,
+	token.SlashEqualToken:   token.SlashToken,
+	token.PercentEqualToken: token.PercentToken,
+}
+
+// IsAssignable determines if an expression can be the target of an assignment or increment
+func IsAssignable(target Expr) bool {
+	switch target.(type) {
+	case Variable, Index:
+		return true
+	}
+	return false
+}
+
+// AssignTo builds an assignment of value to target, reporting false when target is not assignable.
+// A list of assignable targets destructures the assigned list, as in [a, b] = [b, a].
+func AssignTo(target Expr, operation token.Token, value Expr) (Expr, bool) {
+	switch t := target.(type) {
+	case Variable:
+		return NewAssign(t.name, operation, value), true
+	case Index:
+		return NewIndexSet(t.object, t.bracket, t.index, operation, value), true
+	case ListLiteral:
+		if operation.TokenType != token.EqualToken || !isDestructurable(t) {
+			return nil, false
+		}
+		return NewAssignList(t.bracket, t.elements, value), true
+	}
+	return nil, false
+}
+
+func isDestructurable(list ListLiteral) bool {
+	for _, element := range list.elements {
+		if nested, ok := element.(ListLiteral); ok {
+			if !isDestructurable(nested) {
+				return false
+			}
+		} else if !IsAssignable(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// compound computes the value stored by an assignment, applying the binary operator of a compound assignment to the current value
+func compound(operation token.Token, current, value interface{}) (interface{}, error) {
+	if operation.TokenType == token.EqualToken {
+		return value, nil
+	}
+	operator := operation
+	operator.TokenType = compoundOperators[operation.TokenType]
+	return binaryOperation(operator, current, value)
+}
+
+// step adds or subtracts one from a value for an increment or decrement
+func step(operation token.Token, value interface{}) (interface{}, error) {
+	err := checkNumberOperand(operation, value)
+	if err != nil {
+		return nil, err
+	}
+	operator := operation
+	if operation.TokenType == token.PlusPlusToken {
+		operator.TokenType = token.PlusToken
+	} else {
+		operator.TokenType = token.MinusToken
+	}
+	return arithmetic(operator, value, int64(1))
+}
@@ -0,0 +1,21 @@
+package expr_test
+
+import "testing"
+
+func TestCompoundAssignment(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "var a = 1; a += 2; print a;", output: "3\n"},
+		{source: "var a = 10; a -= 3; a *= 2; a /= 7; print a;", output: "2\n"},
+		{source: "var a = 10; a %= 4; print a;", output: "2\n"},
+		{source: `var s = "a"; s += "b"; print s;`, output: "ab\n"},
+		{source: "var a = 1; print a += 1;", output: "2\n"},
+		{source: "var a = 1; print a++; print a;", output: "1\n2\n"},
+		{source: "var a = 1; print ++a; print a;", output: "2\n2\n"},
+		{source: "var a = 1; print a--; print --a;", output: "1\n-1\n"},
+		{source: "var xs = [1, 2]; xs[1] += 5; xs[0]++; print xs;", output: "[2, 7]\n"},
+		{source: "var a = 1.5; a++; print a;", output: "2.5\n"},
+		{source: `var a = "x"; a++;`, err: "Operand must be a number."},
+		{source: "var a = 9223372036854775807; a++;", err: "Integer overflow."},
+		{source: "b += 1;", err: "Undefined variable 'b'."},
+	})
+}
@@ -6,14 +6,108 @@ type AstPrinter interface {
 	ToString() string
 }
 
+func (a Assign) ToString() string {
+	return parenthesize(a.operation.Lexeme+" "+a.name.Lexeme, a.value)
+}
+
+func (a AssignList) ToString() string {
+	exprs := append([]Expr{}, a.targets...)
+	return parenthesize("= list", append(exprs, a.value)...)
+}
+
 func (b Binary) ToString() string {
 	return parenthesize(b.operation.Lexeme, b.left, b.right)
 }
 
+func (c Call) ToString() string {
+	return parenthesize("call", append([]Expr{c.callee}, c.arguments...)...)
+}
+
+func (c Conditional) ToString() string {
+	return parenthesize("?:", c.condition, c.thenBranch, c.elseBranch)
+}
+
+func (g Get) ToString() string {
+	return parenthesize("."+g.name.Lexeme, g.object)
+}
+
 func (g Grouping) ToString() string {
 	return parenthesize("group", g.expression)
 }
 
+func (i Increment) ToString() string {
+	if i.prefix {
+		return parenthesize("prefix "+i.operation.Lexeme, i.target)
+	}
+	return parenthesize("postfix "+i.operation.Lexeme, i.target)
+}
+
+func (m MapLiteral) ToString() string {
+	entries := []Expr{}
+	for i, key := range m.keys {
+		entries = append(entries, key, m.values[i])
+	}
+	return parenthesize("map", entries...)
+}
+
+func (m Match) ToString() string {
+	out := "(match " + m.subject.(AstPrinter).ToString()
+	for i, pattern := range m.patterns {
+		out += " (" + pattern.(AstPrinter).ToString()
+		if m.guards[i] != nil {
+			out += " if " + m.guards[i].(AstPrinter).ToString()
+		}
+		out += " => " + m.bodies[i].(AstPrinter).ToString() + ")"
+	}
+	out += ")"
+	return out
+}
+
+func (a AlternativePattern) ToString() string {
+	return parenthesize("|", patternExprs(a.alternatives)...)
+}
+
+func (b BindingPattern) ToString() string {
+	return b.name.Lexeme
+}
+
+func (l ListPattern) ToString() string {
+	out := parenthesize("list", patternExprs(l.elements)...)
+	if l.rest != nil {
+		out = out[:len(out)-1] + " ..." + l.rest.(AstPrinter).ToString() + ")"
+	}
+	return out
+}
+
+func (l LiteralPattern) ToString() string {
+	return l.value.(AstPrinter).ToString()
+}
+
+func (m MapPattern) ToString() string {
+	out := "(map"
+	for i, key := range m.keys {
+		out += fmt.Sprintf(" %v ", key) + m.values[i].(AstPrinter).ToString()
+	}
+	out += ")"
+	return out
+}
+
+func (w WildcardPattern) ToString() string {
+	return w.name.Lexeme
+}
+
+func (i Index) ToString() string {
+	return parenthesize("index", i.object, i.index)
+}
+
+func (s IndexSet) ToString() string {
+	return parenthesize(s.operation.Lexeme+" index", s.object, s.index, s.value)
+}
+
+func (l ListLiteral) ToString() string {
+	return parenthesize("list", l.elements...)
+}
+
 func (l Literal) ToString() string {
 	if l.value == nil {
 		return "null"
@@ -21,10 +115,26 @@ func (l Literal) ToString() string {
 	return fmt.Sprintf("%v", l.value)
 }
 
+func (l Logical) ToString() string {
+	return parenthesize(l.operation.Lexeme, l.left, l.right)
+}
+
+func (r RangeLiteral) ToString() string {
+	return parenthesize(r.operation.Lexeme, r.start, r.end)
+}
+
+func (s Slice) ToString() string {
+	return parenthesize("slice", s.object, bound(s.start), bound(s.end))
+}
+
 func (u Unary) ToString() string {
 	return parenthesize(u.operation.Lexeme, u.right)
 }
 
+func (v Variable) ToString() string {
+	return v.name.Lexeme
+}
+
 func parenthesize(name string, exprs ...Expr) string {
 	out := "(" + name
 	for _, expr := range exprs {
@@ -34,3 +144,20 @@ func parenthesize(name string, exprs ...Expr) string {
 	out += ")"
 	return out
 }
+
+// bound substitutes an omitted slice bound with nil
+func bound(e Expr) Expr {
+	if e == nil {
+		return NewLiteral(nil)
+	}
+	return e
+}
+
+// patternExprs converts patterns for printing with parenthesize
+func patternExprs(patterns []Pattern) []Expr {
+	exprs := make([]Expr, len(patterns))
+	for i, pattern := range patterns {
+		exprs[i] = pattern
+	}
+	return exprs
+}
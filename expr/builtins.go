@@ -0,0 +1,34 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+func init() {
+	DefineNative(NewNative(NewSignature("freeze", []string{"value"}, 1, false), freeze))
+}
+
+// freeze deeply freezes lists and maps so scripts can't modify them, returning the value
+func freeze(paren token.Token, arguments []interface{}) (interface{}, error) {
+	deepFreeze(arguments[0])
+	return arguments[0], nil
+}
+
+func deepFreeze(value interface{}) {
+	switch v := value.(type) {
+	case *List:
+		if v.Frozen {
+			return
+		}
+		v.Frozen = true
+		for _, element := range v.Elements {
+			deepFreeze(element)
+		}
+	case *Map:
+		if v.Frozen {
+			return
+		}
+		v.Frozen = true
+		for _, entry := range v.Entries() {
+			deepFreeze(entry.Value)
+		}
+	}
+}
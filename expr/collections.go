@@ -0,0 +1,433 @@
+package expr
+
+import (
+	"sort"
+
+	"github.com/levi/holo/token"
+)
+
+// Builtins working over collections. Those taking an iterable accept anything for ... in can iterate,
+// consuming it an element at a time except for sort and reverse, and always return new lists rather than
+// modifying their arguments. Callbacks are called from the builtin's call site, so errors and stack traces
+// point at it.
+//
+//	map(xs, f)               list of f(x) for each x
+//	filter(xs, f)            list of each x for which f(x) is truthy
+//	reduce(xs, f, initial?)  f(f(initial, x1), x2)... starting from the first x without initial
+//	sort(xs, compare?)       sorted list; compare(a, b) returns a negative number, 0 or a positive number
+//	reverse(xs)              list in reverse order, or a reversed string
+//	zip(...lists)            list of lists of the elements at each index, as long as the shortest list
+//	enumerate(xs, start?)    list of [index, x] pairs, counting from start or 0
+//	keys(m), values(m)       lists of the keys or values of a map in insertion order
+//	any(xs, f?), all(xs, f?) whether f(x), or x itself, is truthy for any or all x
+//	sum(xs, start?)          start, by default 0, plus each x
+//	unique(xs)               list of the xs without later equal ones
+//	groupBy(xs, f)           map of each f(x) to the list of xs it was returned for
+func init() {
+	defineCollectionNative("map", []string{"iterable", "function"}, 2, false, collectionMap)
+	defineCollectionNative("filter", []string{"iterable", "function"}, 2, false, collectionFilter)
+	defineCollectionNative("reduce", []string{"iterable", "function", "initial"}, 2, false, collectionReduce)
+	defineCollectionNative("sort", []string{"iterable", "compare"}, 1, false, collectionSort)
+	defineCollectionNative("reverse", []string{"iterable"}, 1, false, collectionReverse)
+	defineCollectionNative("zip", []string{"lists"}, 0, true, collectionZip)
+	defineCollectionNative("enumerate", []string{"iterable", "start"}, 1, false, collectionEnumerate)
+	defineCollectionNative("keys", []string{"map"}, 1, false, collectionKeys)
+	defineCollectionNative("values", []string{"map"}, 1, false, collectionValues)
+	defineCollectionNative("any", []string{"iterable", "function"}, 1, false, collectionAny)
+	defineCollectionNative("all", []string{"iterable", "function"}, 1, false, collectionAll)
+	defineCollectionNative("sum", []string{"iterable", "start"}, 1, false, collectionSum)
+	defineCollectionNative("unique", []string{"iterable"}, 1, false, collectionUnique)
+	defineCollectionNative("groupBy", []string{"iterable", "function"}, 2, false, collectionGroupBy)
+}
+
+// defineCollectionNative registers a builtin given its signature to report bad arguments
+func defineCollectionNative(name string, params []string, required int, variadic bool, function func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error)) {
+	signature := NewSignature(name, params, required, variadic)
+	DefineNative(NewNative(signature, func(paren token.Token, arguments []interface{}) (interface{}, error) {
+		return function(paren, signature, arguments)
+	}))
+}
+
+// forEach calls f with each element of an iterator in turn, stopping at the first error
+func forEach(iterator Iterator, f func(element interface{}) error) error {
+	for {
+		element, ok, err := iterator.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if err := f(element); err != nil {
+			return err
+		}
+	}
+}
+
+// callback calls a function passed to a builtin with positional arguments
+func callback(paren token.Token, function Callable, arguments ...interface{}) (interface{}, error) {
+	return call(paren, function, arguments, make([]string, len(arguments)))
+}
+
+func collectionMap(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	function, err := callableArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	results := []interface{}{}
+	err = forEach(elements, func(element interface{}) error {
+		result, err := callback(paren, function, element)
+		results = append(results, result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(results), nil
+}
+
+func collectionFilter(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	function, err := callableArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	results := []interface{}{}
+	err = forEach(elements, func(element interface{}) error {
+		keep, err := callback(paren, function, element)
+		if isTruthy(keep) {
+			results = append(results, element)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(results), nil
+}
+
+func collectionReduce(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	function, err := callableArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only an omitted initial value starts from the first element, so nil can be an initial value
+	accumulator := arguments[2]
+	if _, ok := accumulator.(omitted); ok {
+		first, ok, err := elements.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, NewRuntimeError(paren, "Cannot reduce an empty iterable without an initial value.")
+		}
+		accumulator = first
+	}
+	err = forEach(elements, func(element interface{}) error {
+		var err error
+		accumulator, err = callback(paren, function, accumulator, element)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accumulator, nil
+}
+
+// collectionSort sorts stably, by compare or else in the natural order of numbers or strings
+func collectionSort(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := elementsArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	var compare Callable
+	if !defaulted(arguments, 1) {
+		compare, err = callableArgument(paren, signature, arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := append([]interface{}{}, elements...)
+	// The first error stops comparisons having any effect and is returned once sorting ends
+	var sortErr error
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+		var c int
+		c, sortErr = compareElements(paren, compare, sorted[i], sorted[j])
+		return c < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+	return NewList(sorted), nil
+}
+
+// compareElements orders two elements with a comparator, or naturally without one
+func compareElements(paren token.Token, compare Callable, a, b interface{}) (int, error) {
+	if compare != nil {
+		result, err := callback(paren, compare, a, b)
+		if err != nil {
+			return 0, err
+		}
+		if !isNumber(result) {
+			return 0, NewRuntimeError(paren, "Comparator must return a number.")
+		}
+		return compareNumbers(result, int64(0)), nil
+	}
+
+	if isNumber(a) && isNumber(b) {
+		return compareNumbers(a, b), nil
+	}
+	l, lOk := a.(string)
+	r, rOk := b.(string)
+	if !lOk || !rOk {
+		return 0, NewRuntimeError(paren, "Cannot compare "+format(a, true, make(map[interface{}]bool))+" and "+format(b, true, make(map[interface{}]bool))+" without a comparator.")
+	}
+	if l < r {
+		return -1, nil
+	} else if l > r {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func collectionReverse(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	if s, ok := arguments[0].(string); ok {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	}
+
+	elements, err := elementsArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	reversed := make([]interface{}, len(elements))
+	for i, element := range elements {
+		reversed[len(elements)-1-i] = element
+	}
+	return NewList(reversed), nil
+}
+
+func collectionZip(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	lists := arguments[0].(*List).Elements
+	if len(lists) == 0 {
+		return NewList([]interface{}{}), nil
+	}
+	length := -1
+	for _, l := range lists {
+		list, ok := l.(*List)
+		if !ok {
+			return nil, argumentError(paren, signature, 0, "lists")
+		}
+		if length < 0 || len(list.Elements) < length {
+			length = len(list.Elements)
+		}
+	}
+
+	zipped := make([]interface{}, length)
+	for i := range zipped {
+		row := make([]interface{}, len(lists))
+		for j, l := range lists {
+			row[j] = l.(*List).Elements[i]
+		}
+		zipped[i] = NewList(row)
+	}
+	return NewList(zipped), nil
+}
+
+func collectionEnumerate(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	start := int64(0)
+	if !defaulted(arguments, 1) {
+		start, err = intArgument(paren, signature, arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pairs := []interface{}{}
+	err = forEach(elements, func(element interface{}) error {
+		pairs = append(pairs, NewList([]interface{}{start + int64(len(pairs)), element}))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(pairs), nil
+}
+
+func collectionKeys(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	m, err := mapArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]interface{}, m.Len())
+	for i, entry := range m.Entries() {
+		keys[i] = entry.Key
+	}
+	return NewList(keys), nil
+}
+
+func collectionValues(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	m, err := mapArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, m.Len())
+	for i, entry := range m.Entries() {
+		values[i] = entry.Value
+	}
+	return NewList(values), nil
+}
+
+func collectionAny(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return quantify(paren, signature, arguments, true)
+}
+
+func collectionAll(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return quantify(paren, signature, arguments, false)
+}
+
+// quantify finds whether any element is truthy, or with any false whether all are, stopping at the first that decides
+func quantify(paren token.Token, signature *Signature, arguments []interface{}, any bool) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	var function Callable
+	if !defaulted(arguments, 1) {
+		function, err = callableArgument(paren, signature, arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		element, ok, err := elements.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return !any, nil
+		}
+		value := element
+		if function != nil {
+			value, err = callback(paren, function, element)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if isTruthy(value) == any {
+			return any, nil
+		}
+	}
+}
+
+func collectionSum(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	total := arguments[1]
+	if defaulted(arguments, 1) {
+		total = int64(0)
+	}
+	plus := token.Token{TokenType: token.PlusToken, Lexeme: "+", Line: paren.Line}
+	err = forEach(elements, func(element interface{}) error {
+		var err error
+		total, err = binaryOperation(plus, total, element)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+// collectionUnique keeps the first of equal elements, comparing unhashable elements one by one
+func collectionUnique(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := NewMap()
+	results := []interface{}{}
+	err = forEach(elements, func(element interface{}) error {
+		if isHashable(element) {
+			if seen.Has(element) {
+				return nil
+			}
+			seen.Set(element, true)
+		} else if containsEqual(results, element) {
+			return nil
+		}
+		results = append(results, element)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(results), nil
+}
+
+func containsEqual(elements []interface{}, value interface{}) bool {
+	for _, element := range elements {
+		if isEqual(element, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func collectionGroupBy(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements, err := iterableArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	function, err := callableArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := NewMap()
+	err = forEach(elements, func(element interface{}) error {
+		key, err := callback(paren, function, element)
+		if err != nil {
+			return err
+		}
+		group, ok := groups.Get(key)
+		if !ok {
+			group = NewList([]interface{}{})
+			if !groups.Set(key, group) {
+				return NewRuntimeError(paren, "Map keys must be strings, numbers, bools or nil.")
+			}
+		}
+		list := group.(*List)
+		list.Elements = append(list.Elements, element)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
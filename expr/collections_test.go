@@ -0,0 +1,78 @@
+package expr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/levi/holo/expr"
+)
+
+// Callbacks shared by the collection tests, as holo has no function literals
+const callbacks = `
+fn double(x) { return x * 2; }
+fn odd(x) { return x % 2 == 1; }
+fn add(a, b) { return a + b; }
+fn pair(a, b) { return [a, b]; }
+fn descending(a, b) { return b - a; }
+fn parity(x) { return x % 2; }
+`
+
+func TestCollections(t *testing.T) {
+	tests := []scriptTest{
+		{source: `print map([1, 2, 3], double), map([], double), map(1..4, double);`, output: "[2, 4, 6] [] [2, 4, 6]\n"},
+		{source: `print filter(0..6, odd), filter([2], odd);`, output: "[1, 3, 5] []\n"},
+		{source: `print reduce([1, 2, 3], add), reduce([], add, 10), reduce(["b"], add, "a");`, output: "6 10 ab\n"},
+		// nil is an initial value rather than a missing one
+		{source: `print reduce([1], pair, nil);`, output: "[nil, 1]\n"},
+		{source: `print reduce([], add, nil);`, output: "nil\n"},
+		{source: `print sort([3, 1, 2]), sort([3, 1, 2], descending), sort(["b", "a"]), sort([]);`, output: "[1, 2, 3] [3, 2, 1] [\"a\", \"b\"] []\n"},
+		{source: `print reverse([1, 2, 3]), reverse("héllo"), reverse([]);`, output: "[3, 2, 1] olléh []\n"},
+		{source: `print zip([1, 2, 3], ["a", "b"]), zip([1]), zip();`, output: "[[1, \"a\"], [2, \"b\"]] [[1]] []\n"},
+		{source: `print enumerate(["a", "b"]), enumerate(["a"], 1);`, output: "[[0, \"a\"], [1, \"b\"]] [[1, \"a\"]]\n"},
+		{source: `var m = {"b": 1, "a": 2}; print keys(m), values(m), keys({});`, output: "[\"b\", \"a\"] [1, 2] []\n"},
+		{source: `print any([nil, false]), any([false, 0]), any([]), any([2, 3], odd), any([2], odd);`, output: "false true false true false\n"},
+		{source: `print all([1, 0]), all([1, nil]), all([]), all([1, 3], odd), all([1, 2], odd);`, output: "true false true true false\n"},
+		{source: `print sum([1, 2, 3]), sum([]), sum([0.5, 1], 1), sum(["b"], "a");`, output: "6 0 2.5 ab\n"},
+		{source: `print unique([1, 2, 1, "a", "a", nil, nil]), unique([]);`, output: "[1, 2, \"a\", nil] []\n"},
+		{source: `print groupBy([1, 2, 3, 4], parity), groupBy([], parity);`, output: "{1: [1, 3], 0: [2, 4]} {}\n"},
+		// Iterables are consumed one element at a time, so deciding early doesn't walk a whole range or iterator
+		{source: `print any(0..10000000000), all(0..10000000000, odd), any(0..10000000000, odd);`, output: "true false true\n"},
+		{source: `fn naturals() { var n = 0; fn next() { n = n + 1; return n; } return {"next": next}; } fn big(x) { return x > 3; } print any(iterable(naturals), big);`, output: "true\n"},
+		{source: `fn three() { var n = 0; fn next() { if (n == 3) return done; n = n + 1; return n; } return {"next": next}; } var xs = iterable(three); print reduce(xs, add), sum(xs), enumerate(xs, 1), unique(xs), sort(xs, descending);`, output: "6 6 [[1, 1], [2, 2], [3, 3]] [1, 2, 3] [3, 2, 1]\n"},
+		// Builtins return new lists, leaving their arguments alone
+		{source: `var xs = [3, 1, 2]; sort(xs); reverse(xs); map(xs, double); print xs;`, output: "[3, 1, 2]\n"},
+		// and iterate over lists as they were when passed
+		{source: `var xs = [1, 2, 3]; fn change(x) { xs[2] = 10; return x; } print map(xs, change), xs;`, output: "[1, 2, 3] [1, 2, 10]\n"},
+		{source: `print map([1]);`, err: "Expected 2 arguments but got 1."},
+		{source: `print map([1], 2);`, err: "Argument 'function' of map() must be a function."},
+		{source: `print map(1, double);`, err: "Argument 'iterable' of map() must be iterable."},
+		{source: `fn iter() { return {}; } print sum(iterable(iter));`, err: "iter() must return a map with a next() function."},
+		{source: `print reduce([], add);`, err: "Cannot reduce an empty iterable without an initial value."},
+		{source: `print sort([1, "a"]);`, err: "Cannot compare \"a\" and 1 without a comparator."},
+		{source: `fn bad(a, b) { return "x"; } print sort([1, 2], bad);`, err: "Comparator must return a number."},
+		{source: `print zip([1], 2);`, err: "Argument 'lists' of zip() must be lists."},
+		{source: `print enumerate([1], "a");`, err: "Argument 'start' of enumerate() must be an integer."},
+		{source: `print keys([1]);`, err: "Argument 'map' of keys() must be a map."},
+		{source: `print sum(["a"]);`, err: "Operands must be two numbers or two strings."},
+		{source: `fn wrap(x) { return [x]; } print groupBy([1], wrap);`, err: "Map keys must be strings, numbers, bools or nil."},
+		{source: `print map(["a"], double);`, err: "Operands must be a number."},
+	}
+	for i := range tests {
+		tests[i].source = callbacks + tests[i].source
+	}
+	runScripts(t, tests)
+}
+
+// Errors in callbacks report the builtin's call site beneath the callback
+func TestCallbackStack(t *testing.T) {
+	source := "fn boom(x) {\nreturn x / 0;\n}\nprint map([1], boom);"
+	_, err := execute(t, source, expr.Interpret)
+	runtimeError, ok := err.(*expr.RuntimeError)
+	if !ok {
+		t.Fatalf("got error %v, want a runtime error", err)
+	}
+	stack := []string{"[line 2] in boom()", "[line 4] in map()", "[line 4] in script"}
+	if !reflect.DeepEqual(runtimeError.Stack, stack) {
+		t.Errorf("got stack %q, want %q", runtimeError.Stack, stack)
+	}
+}
@@ -0,0 +1,19 @@
+package expr_test
+
+import "testing"
+
+func TestConditional(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print true ? 1 : 2;", output: "1\n"},
+		{source: "print nil ? 1 : 2;", output: "2\n"},
+		{source: "print 0 ? 1 : 2;", output: "1\n"},
+		{source: "print false ? 1 : true ? 2 : 3;", output: "2\n"},
+		{source: "print nil ?? 5;", output: "5\n"},
+		{source: "print false ?? 5;", output: "false\n"},
+		{source: "print nil ?? nil ?? 3;", output: "3\n"},
+		// The branch not taken is never evaluated
+		{source: "print true ? 1 : 1 / 0;", output: "1\n"},
+		{source: "print 1 ?? 1 / 0;", output: "1\n"},
+		{source: "print false ? 1 : 1 / 0;", err: "Division by zero."},
+	})
+}
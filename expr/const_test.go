@@ -0,0 +1,60 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/levi/holo/expr"
+)
+
+func TestConstants(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "const x = 1; print x;", output: "1\n"},
+		{source: "const x = 1; { var x = 2; x = 3; print x; } print x;", output: "3\n1\n"},
+		{source: "const x = 1; fn f() { var x = 2; x += 1; return x; } print f();", output: "3\n"},
+		{source: "const xs = [1]; xs[0] = 2; print xs;", output: "[2]\n"},
+		// Assignments to constants are found before the script runs
+		{source: "print 1; const x = 1; x = 2;", err: "Cannot assign to constant 'x'."},
+		{source: "const x = 1; fn f() { x += 1; }", err: "Cannot assign to constant 'x'."},
+		{source: "const x = 1; x++;", err: "Cannot assign to constant 'x'."},
+		{source: "const x = 1; [x] = [2];", err: "Cannot assign to constant 'x'."},
+		// So are declarations replacing a constant of the same scope
+		{source: "print 1; const cfg = 1; var cfg = 2;", err: "Cannot redeclare constant 'cfg'."},
+		{source: "const cfg = 1; fn cfg() {}", err: "Cannot redeclare constant 'cfg'."},
+		{source: "const cfg = 1; import \"lib\" as cfg;", err: "Cannot redeclare constant 'cfg'."},
+		{source: "const cfg = 1; var [a, cfg] = [1, 2];", err: "Cannot redeclare constant 'cfg'."},
+		{source: "const cfg = 1; const cfg = 2;", err: "Cannot redeclare constant 'cfg'."},
+		{source: "{ const cfg = 1; var cfg = 2; }", err: "Cannot redeclare constant 'cfg'."},
+		{source: "var cfg = 1; const cfg = 2; print cfg;", output: "2\n"},
+	})
+}
+
+// Constants declared by an earlier run of the main module, as in the REPL, are checked while running
+func TestRedeclareGlobalConstant(t *testing.T) {
+	_, err := execute(t, "const replConfig = 1;", expr.Interpret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range []string{"var replConfig = 2;", "fn replConfig() {}", "const replConfig = 2;", "var [replConfig] = [2];"} {
+		_, err = execute(t, source, expr.Interpret)
+		if err == nil || err.Error() != "Cannot redeclare constant 'replConfig'." {
+			t.Errorf("%q: got error %v, want a redeclaration error", source, err)
+		}
+	}
+	output, err := execute(t, "print replConfig;", expr.Interpret)
+	if err != nil || output != "1\n" {
+		t.Errorf("printed %q with error %v, want 1", output, err)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "var xs = freeze([1, [2]]); print xs;", output: "[1, [2]]\n"},
+		{source: "print freeze(1); print freeze(nil);", output: "1\nnil\n"},
+		{source: "var xs = freeze([1]); var ys = xs[:]; ys[0] = 2; print ys;", output: "[2]\n"},
+		{source: "var xs = freeze([1]); xs[0] = 2;", err: "Cannot modify a frozen list."},
+		{source: "var xs = freeze([[1]]); xs[0][0] = 2;", err: "Cannot modify a frozen list."},
+		{source: `var m = freeze({"a": {"b": 1}}); m["a"]["b"] = 2;`, err: "Cannot modify a frozen map."},
+		{source: `var m = freeze({}); m["a"] = 1;`, err: "Cannot modify a frozen map."},
+		{source: "var xs = [1]; xs[0] = xs; freeze(xs); print xs;", output: "[[...]]\n"},
+	})
+}
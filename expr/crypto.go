@@ -0,0 +1,115 @@
+package expr
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/url"
+
+	"github.com/levi/holo/token"
+)
+
+// The crypto module hashes strings to hex digests, and the encoding module converts strings to and from
+// base64, hex and URL query escaping. Decoding malformed input raises a runtime error.
+var hashes = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha1":   sha1.New,
+	"md5":    md5.New,
+}
+
+func init() {
+	c := DefineNativeModule("crypto")
+	for name, h := range hashes {
+		c.exportNative(name, []string{"data"}, 1, false, hashFunction(h))
+	}
+	c.exportNative("hmac", []string{"key", "data", "algorithm"}, 2, false, cryptoHmac)
+
+	e := DefineNativeModule("encoding")
+	e.exportNative("base64Encode", []string{"data"}, 1, false, encoder(func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}))
+	e.exportNative("base64Decode", []string{"text"}, 1, false, decoder(func(s string) (string, error) {
+		data, err := base64.StdEncoding.DecodeString(s)
+		return string(data), err
+	}))
+	e.exportNative("hexEncode", []string{"data"}, 1, false, encoder(func(s string) string {
+		return hex.EncodeToString([]byte(s))
+	}))
+	e.exportNative("hexDecode", []string{"text"}, 1, false, decoder(func(s string) (string, error) {
+		data, err := hex.DecodeString(s)
+		return string(data), err
+	}))
+	e.exportNative("urlEncode", []string{"data"}, 1, false, encoder(url.QueryEscape))
+	e.exportNative("urlDecode", []string{"text"}, 1, false, decoder(url.QueryUnescape))
+}
+
+// hashFunction adapts a hash to a native returning the hex digest of a string
+func hashFunction(h func() hash.Hash) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		data, err := stringArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		digest := h()
+		digest.Write([]byte(data))
+		return hex.EncodeToString(digest.Sum(nil)), nil
+	}
+}
+
+// cryptoHmac returns the hex HMAC of data, using sha256 unless another hash is named
+func cryptoHmac(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	key, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := stringArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	algorithm := "sha256"
+	if !defaulted(arguments, 2) {
+		algorithm, err = stringArgument(paren, signature, arguments, 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	h, ok := hashes[algorithm]
+	if !ok {
+		return nil, NewRuntimeError(paren, "Unknown hash algorithm '"+algorithm+"'.")
+	}
+
+	mac := hmac.New(h, []byte(key))
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// encoder adapts an encoding of strings to a native
+func encoder(encode func(string) string) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		data, err := stringArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		return encode(data), nil
+	}
+}
+
+// decoder adapts a decoding of strings to a native, raising runtime errors for malformed input
+func decoder(decode func(string) (string, error)) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		text, err := stringArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		data, err := decode(text)
+		if err != nil {
+			return nil, NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,33 @@
+package expr_test
+
+import "testing"
+
+func TestCrypto(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print crypto.sha256("abc");`, output: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad\n"},
+		{source: `print crypto.sha1("abc"), crypto.md5("");`, output: "a9993e364706816aba3e25717850c26c9cd0d89d d41d8cd98f00b204e9800998ecf8427e\n"},
+		{
+			source: `print crypto.hmac("key", "The quick brown fox jumps over the lazy dog");`,
+			output: "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8\n",
+		},
+		{
+			source: `print crypto.hmac("key", "The quick brown fox jumps over the lazy dog", "md5");`,
+			output: "80070713463e7749b90c2dc24911e275\n",
+		},
+		{source: `print crypto.hmac("k", "x", "sha512");`, err: "Unknown hash algorithm 'sha512'."},
+		{source: `print crypto.sha256(1);`, err: "Argument 'data' of crypto.sha256() must be a string."},
+	})
+}
+
+func TestEncoding(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print encoding.base64Encode("héllo"), encoding.base64Decode("aMOpbGxv"), encoding.base64Encode("");`, output: "aMOpbGxv héllo \n"},
+		{source: `print encoding.hexEncode("hi"), encoding.hexDecode("6869"), encoding.hexDecode("6A");`, output: "6869 hi j\n"},
+		{source: `print encoding.urlEncode("a b&c=d"), encoding.urlDecode("a+b%26c");`, output: "a+b%26c%3Dd a b&c\n"},
+		{source: `var s = "héllo wörld"; print encoding.base64Decode(encoding.base64Encode(s)) == s, encoding.urlDecode(encoding.urlEncode(s)) == s;`, output: "true true\n"},
+		{source: `print encoding.base64Decode("!!");`, err: "encoding.base64Decode() failed: illegal base64 data at input byte 0."},
+		{source: `print encoding.hexDecode("zz");`, err: "encoding.hexDecode() failed: encoding/hex: invalid byte: U+007A 'z'."},
+		{source: `print encoding.urlDecode("%zz");`, err: "encoding.urlDecode() failed: invalid URL escape \"%zz\"."},
+		{source: `print encoding.hexEncode(nil);`, err: "Argument 'data' of encoding.hexEncode() must be a string."},
+	})
+}
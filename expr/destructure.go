@@ -0,0 +1,109 @@
+package expr
+
+import (
+	"fmt"
+
+	"github.com/levi/holo/token"
+)
+
+// Destructurer binds the parts of a value to the variables of a declaration pattern,
+// failing with a runtime error when the value does not have the pattern's shape
+type Destructurer interface {
+	Destructure(value interface{}, bindings map[string]interface{}) error
+}
+
+func (b BindingPattern) Destructure(value interface{}, bindings map[string]interface{}) error {
+	bindings[b.name.Lexeme] = value
+	return nil
+}
+
+func (l ListPattern) Destructure(value interface{}, bindings map[string]interface{}) error {
+	list, ok := value.(*List)
+	if !ok {
+		return NewRuntimeError(l.bracket, "Cannot destructure "+stringify(value)+" as a list.")
+	}
+	if l.rest == nil && len(list.Elements) != len(l.elements) {
+		return NewRuntimeError(l.bracket, fmt.Sprintf("Expected a list of %d elements but got %d.", len(l.elements), len(list.Elements)))
+	}
+	if len(list.Elements) < len(l.elements) {
+		return NewRuntimeError(l.bracket, fmt.Sprintf("Expected a list of at least %d elements but got %d.", len(l.elements), len(list.Elements)))
+	}
+
+	for i, element := range l.elements {
+		err := destructure(element, list.Elements[i], bindings)
+		if err != nil {
+			return err
+		}
+	}
+	if l.rest != nil {
+		return destructure(l.rest, restOf(list, len(l.elements)), bindings)
+	}
+	return nil
+}
+
+func (m MapPattern) Destructure(value interface{}, bindings map[string]interface{}) error {
+	object, ok := value.(*Map)
+	if !ok {
+		return NewRuntimeError(m.brace, "Cannot destructure "+stringify(value)+" as a map.")
+	}
+	for i, key := range m.keys {
+		entry, ok := object.Get(key)
+		if !ok {
+			return NewRuntimeError(m.brace, "Missing key "+format(key, true, nil)+" in destructured map.")
+		}
+		err := destructure(m.values[i], entry, bindings)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w WildcardPattern) Destructure(value interface{}, bindings map[string]interface{}) error {
+	return nil
+}
+
+func destructure(pattern Pattern, value interface{}, bindings map[string]interface{}) error {
+	return pattern.(Destructurer).Destructure(value, bindings)
+}
+
+// restOf copies the elements of a list from offset onwards into a new list
+func restOf(list *List, offset int) *List {
+	rest := make([]interface{}, len(list.Elements)-offset)
+	copy(rest, list.Elements[offset:])
+	return NewList(rest)
+}
+
+// assignTarget stores a value into an assignable expression, destructuring lists for list targets
+func assignTarget(bracket token.Token, target Expr, value interface{}) error {
+	switch t := target.(type) {
+	case Variable:
+		return environment.Assign(t.name, value)
+	case Index:
+		object, err := evaluate(t.object)
+		if err != nil {
+			return err
+		}
+		index, err := evaluate(t.index)
+		if err != nil {
+			return err
+		}
+		return setIndex(t.bracket, object, index, value)
+	case ListLiteral:
+		list, ok := value.(*List)
+		if !ok {
+			return NewRuntimeError(t.bracket, "Cannot destructure "+stringify(value)+" as a list.")
+		}
+		if len(list.Elements) != len(t.elements) {
+			return NewRuntimeError(t.bracket, fmt.Sprintf("Expected a list of %d elements but got %d.", len(t.elements), len(list.Elements)))
+		}
+		for i, element := range t.elements {
+			err := assignTarget(t.bracket, element, list.Elements[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return NewRuntimeError(bracket, "Invalid assignment target.")
+}
@@ -0,0 +1,24 @@
+package expr_test
+
+import "testing"
+
+func TestDestructuring(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "var [a, b] = [1, 2]; print a; print b;", output: "1\n2\n"},
+		{source: "var [a, ...rest] = [1, 2, 3]; print a; print rest;", output: "1\n[2, 3]\n"},
+		{source: "var [a, ...rest] = [1]; print a; print rest;", output: "1\n[]\n"},
+		{source: "var [_, [b, c]] = [1, [2, 3]]; print b; print c;", output: "2\n3\n"},
+		{source: `var {name, age} = {"name": "ann", "age": 3, "x": 0}; print name; print age;`, output: "ann\n3\n"},
+		{source: `var {"first name": first, tags: [tag]} = {"first name": "bo", "tags": ["t"]}; print first; print tag;`, output: "bo\nt\n"},
+		{source: "var a = 1; var b = 2; [a, b] = [b, a]; print a; print b;", output: "2\n1\n"},
+		{source: "var xs = [0, 0]; var i = 0; [xs[0], i] = [5, 1]; print xs; print i;", output: "[5, 0]\n1\n"},
+		{source: "var a; var b; var c; [a, [b, c]] = [1, [2, 3]]; print a; print b; print c;", output: "1\n2\n3\n"},
+		{source: "var [a, b] = [1];", err: "Expected a list of 2 elements but got 1."},
+		{source: "var [a, b, ...c] = [1];", err: "Expected a list of at least 2 elements but got 1."},
+		{source: "var [a] = 1;", err: "Cannot destructure 1 as a list."},
+		{source: "var {a} = [1];", err: "Cannot destructure [1] as a map."},
+		{source: `var {a} = {"b": 1};`, err: "Missing key \"a\" in destructured map."},
+		{source: "var a; var b; [a, b] = [1, 2, 3];", err: "Expected a list of 2 elements but got 3."},
+		{source: "var a; [a] = nil;", err: "Cannot destructure nil as a list."},
+	})
+}
@@ -0,0 +1,65 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+// Environment binds variable names to values, deferring to its enclosing environment for unknown names
+type Environment struct {
+	values    map[string]interface{}
+	constants map[string]bool
+	enclosing *Environment
+}
+
+// NewEnvironment allocates an environment nested within enclosing, which is nil for the global environment
+func NewEnvironment(enclosing *Environment) *Environment {
+	e := new(Environment)
+	e.values = make(map[string]interface{})
+	e.constants = make(map[string]bool)
+	e.enclosing = enclosing
+	return e
+}
+
+// Define binds a new variable, replacing any existing binding of the same name
+func (e *Environment) Define(name string, value interface{}) {
+	e.values[name] = value
+	delete(e.constants, name)
+}
+
+// Declare binds a new variable for a declaration, which can't replace a constant of the same scope.
+// The resolver reports these before running, except for constants of the global scope declared by
+// an earlier run, such as a previous line of the REPL.
+func (e *Environment) Declare(name token.Token, value interface{}, constant bool) error {
+	if e.constants[name.Lexeme] {
+		return NewRuntimeError(name, "Cannot redeclare constant '"+name.Lexeme+"'.")
+	}
+	e.values[name.Lexeme] = value
+	if constant {
+		e.constants[name.Lexeme] = true
+	}
+	return nil
+}
+
+// Get looks up the value of a variable
+func (e *Environment) Get(name token.Token) (interface{}, error) {
+	if value, ok := e.values[name.Lexeme]; ok {
+		return value, nil
+	}
+	if e.enclosing != nil {
+		return e.enclosing.Get(name)
+	}
+	return nil, NewRuntimeError(name, "Undefined variable '"+name.Lexeme+"'.")
+}
+
+// Assign updates the value of an existing variable
+func (e *Environment) Assign(name token.Token, value interface{}) error {
+	if _, ok := e.values[name.Lexeme]; ok {
+		if e.constants[name.Lexeme] {
+			return NewRuntimeError(name, "Cannot assign to constant '"+name.Lexeme+"'.")
+		}
+		e.values[name.Lexeme] = value
+		return nil
+	}
+	if e.enclosing != nil {
+		return e.enclosing.Assign(name, value)
+	}
+	return NewRuntimeError(name, "Undefined variable '"+name.Lexeme+"'.")
+}
@@ -0,0 +1,76 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+// Thrown carries a value raised by a throw statement up to the nearest enclosing catch clause
+type Thrown struct {
+	Token token.Token
+	Value interface{}
+	Stack []string
+}
+
+// NewThrown allocates a thrown value raised at token, recording the active calls
+func NewThrown(token token.Token, value interface{}) *Thrown {
+	return &Thrown{
+		token,
+		value,
+		stackTrace(token.Line),
+	}
+}
+
+func (t *Thrown) Error() string {
+	if e, ok := t.Value.(*ErrorObject); ok {
+		return e.Message
+	}
+	return "Uncaught exception: " + stringify(t.Value)
+}
+
+// ErrorObject is the value a catch clause receives for a runtime error, exposing
+// its message, line and stack as properties
+type ErrorObject struct {
+	Message string
+	Line    int
+	Stack   *List
+}
+
+// NewErrorObject allocates an error object for a runtime error
+func NewErrorObject(err *RuntimeError) *ErrorObject {
+	e := new(ErrorObject)
+	e.Message = err.Message
+	e.Line = err.Token.Line
+	stack := make([]interface{}, len(err.Stack))
+	for i, line := range err.Stack {
+		stack[i] = line
+	}
+	e.Stack = NewList(stack)
+	return e
+}
+
+func (e *ErrorObject) String() string {
+	return "Error: " + e.Message
+}
+
+// property looks up a property of an error object
+func (e *ErrorObject) property(name token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "message":
+		return e.Message, nil
+	case "line":
+		return int64(e.Line), nil
+	case "stack":
+		return e.Stack, nil
+	}
+	return nil, NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+// caught converts an error into the value bound by a catch clause, reporting false for
+// errors which cannot be caught such as break and continue
+func caught(err error) (interface{}, bool) {
+	switch e := err.(type) {
+	case *Thrown:
+		return e.Value, true
+	case *RuntimeError:
+		return NewErrorObject(e), true
+	}
+	return nil, false
+}
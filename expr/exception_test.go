@@ -0,0 +1,63 @@
+package expr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/levi/holo/expr"
+)
+
+func TestExceptions(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "try { throw 1; } catch (e) { print e; }", output: "1\n"},
+		{source: `try { throw {"code": 2}; } catch (e) { print e["code"]; }`, output: "2\n"},
+		{source: "try { print 1 / 0; } catch (e) { print e.message; print e.line; }", output: "Division by zero.\n1\n"},
+		{source: "try { print missing; } catch (e) { print e; }", output: "Error: Undefined variable 'missing'.\n"},
+		{source: `try { print 1 + "a"; } catch (e) { print e.message; }`, output: "Operands must be two numbers or two strings.\n"},
+		{source: "try { print 1; } finally { print 2; }", output: "1\n2\n"},
+		{source: "try { throw 1; } catch (e) { print 1; } finally { print 2; }", output: "1\n2\n"},
+		{source: "try { throw 1; } finally { print 2; }", output: "2\n", err: "Uncaught exception: 1"},
+		{source: "fn f() { try { return 1; } finally { print 2; } } print f();", output: "2\n1\n"},
+		{source: "try { try { throw 1; } catch (e) { throw e + 1; } } catch (e) { print e; }", output: "2\n"},
+		{source: "try { throw 1; } catch (e) {} print e;", err: "Undefined variable 'e'."},
+		{source: "try { 1 / 0; } catch (e) { throw e; }", err: "Division by zero."},
+		{source: "try { 1 / 0; } catch (e) { print e.code; }", err: "Undefined property 'code'."},
+		{source: `throw "bad input";`, err: "Uncaught exception: bad input"},
+		{
+			source: "fn inner() { 1 / 0; }\nfn outer() { inner(); }\ntry { outer(); } catch (e) { print e.stack; }",
+			output: "[\"[line 1] in inner()\", \"[line 2] in outer()\", \"[line 3] in script\"]\n",
+		},
+	})
+}
+
+// Uncaught throws report the stack of the throw statement, like runtime errors
+func TestUncaughtStack(t *testing.T) {
+	tests := []struct {
+		source string
+		stack  []string
+	}{
+		{
+			source: "fn inner() {\nthrow 1;\n}\nfn outer() { inner(); }\nouter();",
+			stack:  []string{"[line 2] in inner()", "[line 4] in outer()", "[line 5] in script"},
+		},
+		{
+			source: "fn inner() {\n1 / 0;\n}\ninner();",
+			stack:  []string{"[line 2] in inner()", "[line 4] in script"},
+		},
+		{
+			source: "\nthrow 1;",
+			stack:  []string{"[line 2] in script"},
+		},
+	}
+	for _, test := range tests {
+		_, err := execute(t, test.source, expr.Interpret)
+		runtimeError, ok := err.(*expr.RuntimeError)
+		if !ok {
+			t.Errorf("%q: got error %v, want a runtime error", test.source, err)
+			continue
+		}
+		if !reflect.DeepEqual(runtimeError.Stack, test.stack) {
+			t.Errorf("%q: got stack %q, want %q", test.source, runtimeError.Stack, test.stack)
+		}
+	}
+}
@@ -7,6 +7,34 @@ import (
 type Expr interface {}
 
 
+type Assign struct {
+    name token.Token
+    operation token.Token
+    value Expr
+}
+
+func NewAssign(name token.Token, operation token.Token, value Expr) Assign {
+    return Assign{
+        name,
+        operation,
+        value,
+    }
+}
+
+type AssignList struct {
+    bracket token.Token
+    targets []Expr
+    value Expr
+}
+
+func NewAssignList(bracket token.Token, targets []Expr, value Expr) AssignList {
+    return AssignList{
+        bracket,
+        targets,
+        value,
+    }
+}
+
 type Binary struct {
     left Expr
     operation token.Token
@@ -21,6 +49,48 @@ func NewBinary(left Expr, operation token.Token, right Expr) Binary {
     }
 }
 
+type Call struct {
+    callee Expr
+    paren token.Token
+    arguments []Expr
+    names []string
+}
+
+func NewCall(callee Expr, paren token.Token, arguments []Expr, names []string) Call {
+    return Call{
+        callee,
+        paren,
+        arguments,
+        names,
+    }
+}
+
+type Conditional struct {
+    condition Expr
+    thenBranch Expr
+    elseBranch Expr
+}
+
+func NewConditional(condition Expr, thenBranch Expr, elseBranch Expr) Conditional {
+    return Conditional{
+        condition,
+        thenBranch,
+        elseBranch,
+    }
+}
+
+type Get struct {
+    object Expr
+    name token.Token
+}
+
+func NewGet(object Expr, name token.Token) Get {
+    return Get{
+        object,
+        name,
+    }
+}
+
 type Grouping struct {
     expression Expr
 }
@@ -31,6 +101,64 @@ func NewGrouping(expression Expr) Grouping {
     }
 }
 
+type Increment struct {
+    target Expr
+    operation token.Token
+    prefix bool
+}
+
+func NewIncrement(target Expr, operation token.Token, prefix bool) Increment {
+    return Increment{
+        target,
+        operation,
+        prefix,
+    }
+}
+
+type Index struct {
+    object Expr
+    bracket token.Token
+    index Expr
+}
+
+func NewIndex(object Expr, bracket token.Token, index Expr) Index {
+    return Index{
+        object,
+        bracket,
+        index,
+    }
+}
+
+type IndexSet struct {
+    object Expr
+    bracket token.Token
+    index Expr
+    operation token.Token
+    value Expr
+}
+
+func NewIndexSet(object Expr, bracket token.Token, index Expr, operation token.Token, value Expr) IndexSet {
+    return IndexSet{
+        object,
+        bracket,
+        index,
+        operation,
+        value,
+    }
+}
+
+type ListLiteral struct {
+    bracket token.Token
+    elements []Expr
+}
+
+func NewListLiteral(bracket token.Token, elements []Expr) ListLiteral {
+    return ListLiteral{
+        bracket,
+        elements,
+    }
+}
+
 type Literal struct {
     value interface{}
 }
@@ -41,6 +169,82 @@ func NewLiteral(value interface{}) Literal {
     }
 }
 
+type Logical struct {
+    left Expr
+    operation token.Token
+    right Expr
+}
+
+func NewLogical(left Expr, operation token.Token, right Expr) Logical {
+    return Logical{
+        left,
+        operation,
+        right,
+    }
+}
+
+type MapLiteral struct {
+    brace token.Token
+    keys []Expr
+    values []Expr
+}
+
+func NewMapLiteral(brace token.Token, keys []Expr, values []Expr) MapLiteral {
+    return MapLiteral{
+        brace,
+        keys,
+        values,
+    }
+}
+
+type Match struct {
+    keyword token.Token
+    subject Expr
+    patterns []Pattern
+    guards []Expr
+    bodies []Expr
+}
+
+func NewMatch(keyword token.Token, subject Expr, patterns []Pattern, guards []Expr, bodies []Expr) Match {
+    return Match{
+        keyword,
+        subject,
+        patterns,
+        guards,
+        bodies,
+    }
+}
+
+type RangeLiteral struct {
+    start Expr
+    operation token.Token
+    end Expr
+}
+
+func NewRangeLiteral(start Expr, operation token.Token, end Expr) RangeLiteral {
+    return RangeLiteral{
+        start,
+        operation,
+        end,
+    }
+}
+
+type Slice struct {
+    object Expr
+    bracket token.Token
+    start Expr
+    end Expr
+}
+
+func NewSlice(object Expr, bracket token.Token, start Expr, end Expr) Slice {
+    return Slice{
+        object,
+        bracket,
+        start,
+        end,
+    }
+}
+
 type Unary struct {
     operation token.Token
     right Expr
@@ -53,3 +257,13 @@ func NewUnary(operation token.Token, right Expr) Unary {
     }
 }
 
+type Variable struct {
+    name token.Token
+}
+
+func NewVariable(name token.Token) Variable {
+    return Variable{
+        name,
+    }
+}
+
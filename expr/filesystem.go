@@ -0,0 +1,340 @@
+package expr
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileSystem is the file system used by the fs and path modules: an fs.FS to read from, which
+// also writes. Embedders can replace it, such as with a MemoryFileSystem for tests. Unlike
+// fs.FS, names are paths as scripts give them, which may be absolute or contain "..".
+type FileSystem interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+	// Abs makes a path absolute relative to the working directory
+	Abs(name string) (string, error)
+}
+
+// OSFileSystem is the FileSystem of the host operating system
+type OSFileSystem struct{}
+
+func (OSFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (OSFileSystem) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (OSFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return ioutil.WriteFile(name, data, perm)
+}
+
+func (OSFileSystem) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+func (OSFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (OSFileSystem) Abs(name string) (string, error) {
+	return filepath.Abs(name)
+}
+
+var errNotDir = errors.New("not a directory")
+var errIsDir = errors.New("is a directory")
+var errNotEmpty = errors.New("directory not empty")
+
+// MemoryFileSystem is a FileSystem held in memory, rooted at "/" which is also its working directory.
+// Besides fs.FS it implements fs.ReadFileFS, fs.StatFS and fs.ReadDirFS.
+type MemoryFileSystem struct {
+	// files maps cleaned names to files and directories, with the root directory named "."
+	files map[string]*memoryFile
+}
+
+// memoryFile is a file or directory of a MemoryFileSystem
+type memoryFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// NewMemoryFileSystem allocates an empty in-memory file system
+func NewMemoryFileSystem() *MemoryFileSystem {
+	m := new(MemoryFileSystem)
+	m.files = map[string]*memoryFile{".": {mode: fs.ModeDir | 0755}}
+	return m
+}
+
+// clean converts a script's path to a name within the file system, reporting paths which leave the root
+func (m *MemoryFileSystem) clean(op, name string) (string, error) {
+	if relative := path.Clean(name); relative == ".." || strings.HasPrefix(relative, "../") {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	cleaned := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if cleaned == "" {
+		return ".", nil
+	}
+	return cleaned, nil
+}
+
+// lookup finds the file or directory at a script's path
+func (m *MemoryFileSystem) lookup(op, name string) (string, *memoryFile, error) {
+	cleaned, err := m.clean(op, name)
+	if err != nil {
+		return "", nil, err
+	}
+	file, ok := m.files[cleaned]
+	if !ok {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return cleaned, file, nil
+}
+
+// isDir determines if name is a directory, reporting an error if it exists as a file
+func (m *MemoryFileSystem) isDir(op, name, cleaned string) (bool, error) {
+	file, ok := m.files[cleaned]
+	if !ok {
+		return false, nil
+	}
+	if !file.mode.IsDir() {
+		return false, &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return true, nil
+}
+
+// entries lists the files and directories within a directory, sorted by name
+func (m *MemoryFileSystem) entries(dir string) []fs.DirEntry {
+	entries := []fs.DirEntry{}
+	for name, file := range m.files {
+		if name != "." && path.Dir(name) == dir {
+			entries = append(entries, memoryInfo{path.Base(name), file})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries
+}
+
+func (m *MemoryFileSystem) Open(name string) (fs.File, error) {
+	cleaned, file, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := memoryInfo{path.Base(cleaned), file}
+	if file.mode.IsDir() {
+		return &memoryDir{info: info, path: name, entries: m.entries(cleaned)}, nil
+	}
+	return &memoryReader{info: info, Reader: bytes.NewReader(file.data)}, nil
+}
+
+func (m *MemoryFileSystem) ReadFile(name string) ([]byte, error) {
+	_, file, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if file.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return append([]byte{}, file.data...), nil
+}
+
+func (m *MemoryFileSystem) Stat(name string) (fs.FileInfo, error) {
+	cleaned, file, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return memoryInfo{path.Base(cleaned), file}, nil
+}
+
+func (m *MemoryFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	cleaned, file, err := m.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !file.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+	return m.entries(cleaned), nil
+}
+
+// WriteFile creates or replaces a file, whose directory must already exist
+func (m *MemoryFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	cleaned, err := m.clean("open", name)
+	if err != nil {
+		return err
+	}
+	if file, ok := m.files[cleaned]; ok && file.mode.IsDir() {
+		return &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+	}
+	dir, err := m.isDir("open", name, path.Dir(cleaned))
+	if err != nil {
+		return err
+	}
+	if !dir {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	m.files[cleaned] = &memoryFile{data: append([]byte{}, data...), mode: perm}
+	return nil
+}
+
+func (m *MemoryFileSystem) MkdirAll(name string, perm fs.FileMode) error {
+	cleaned, err := m.clean("mkdir", name)
+	if err != nil {
+		return err
+	}
+	if cleaned == "." {
+		return nil
+	}
+	parts := strings.Split(cleaned, "/")
+	for i := range parts {
+		dir := strings.Join(parts[:i+1], "/")
+		exists, err := m.isDir("mkdir", name, dir)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			m.files[dir] = &memoryFile{mode: fs.ModeDir | perm}
+		}
+	}
+	return nil
+}
+
+// Remove removes a file or an empty directory
+func (m *MemoryFileSystem) Remove(name string) error {
+	cleaned, file, err := m.lookup("remove", name)
+	if err != nil {
+		return err
+	}
+	if cleaned == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	if file.mode.IsDir() && len(m.entries(cleaned)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.files, cleaned)
+	return nil
+}
+
+func (m *MemoryFileSystem) Abs(name string) (string, error) {
+	cleaned, err := m.clean("abs", name)
+	if err != nil {
+		return "", err
+	}
+	if cleaned == "." {
+		return "/", nil
+	}
+	return "/" + cleaned, nil
+}
+
+// memoryInfo describes a file or directory of a MemoryFileSystem, both as an fs.FileInfo and an fs.DirEntry
+type memoryInfo struct {
+	name string
+	file *memoryFile
+}
+
+func (i memoryInfo) Name() string {
+	return i.name
+}
+
+func (i memoryInfo) Size() int64 {
+	return int64(len(i.file.data))
+}
+
+func (i memoryInfo) Mode() fs.FileMode {
+	return i.file.mode
+}
+
+func (i memoryInfo) Type() fs.FileMode {
+	return i.file.mode.Type()
+}
+
+// ModTime is always the zero time, as a MemoryFileSystem doesn't track modification times
+func (i memoryInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i memoryInfo) IsDir() bool {
+	return i.file.mode.IsDir()
+}
+
+func (i memoryInfo) Sys() interface{} {
+	return nil
+}
+
+func (i memoryInfo) Info() (fs.FileInfo, error) {
+	return i, nil
+}
+
+// memoryReader is an open file of a MemoryFileSystem
+type memoryReader struct {
+	*bytes.Reader
+	info memoryInfo
+}
+
+func (r *memoryReader) Stat() (fs.FileInfo, error) {
+	return r.info, nil
+}
+
+func (r *memoryReader) Close() error {
+	return nil
+}
+
+// memoryDir is an open directory of a MemoryFileSystem, listing the entries it held when opened
+type memoryDir struct {
+	info    memoryInfo
+	path    string
+	entries []fs.DirEntry
+}
+
+func (d *memoryDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *memoryDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errIsDir}
+}
+
+func (d *memoryDir) Close() error {
+	return nil
+}
+
+// ReadDir returns the next n entries, or all remaining entries when n <= 0
+func (d *memoryDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 || n > len(d.entries) {
+		if n > 0 && len(d.entries) == 0 {
+			return nil, io.EOF
+		}
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+var fileSystem FileSystem = OSFileSystem{}
+
+// SetFileSystem replaces the file system scripts read and write
+func SetFileSystem(fsys FileSystem) {
+	fileSystem = fsys
+}
@@ -0,0 +1,310 @@
+package expr
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/levi/holo/token"
+)
+
+// Format strings, used by format() and the string format method, substitute values into fields in
+// braces, with {{ and }} standing for literal braces. A field holds an optional value index, which
+// defaults to the next value, and an optional spec after a colon:
+//
+//	{[index][:[[fill]align][sign][0][width][,][.precision][type]]}
+//
+//	align      < left, > right or ^ centered within width, padded with fill or spaces
+//	sign       + to sign positive numbers too, or a space to leave room for a sign
+//	0          pad numbers with zeros after their sign
+//	,          separate thousands with commas
+//	precision  digits after the point for f, e and %, significant digits for g, or the most runes of a string
+//	type       d, or x X o b for hex, octal or binary integers; f e g % for floats; s for any value
+//
+// Numbers align right and everything else left. Without a type, integers format as d, other numbers
+// as f when a precision is given, and values otherwise as they print. Integers and decimals format
+// exactly as f and %.
+func init() {
+	DefineNative(NewNative(NewSignature("format", []string{"template", "values"}, 1, true), formatNative))
+}
+
+func formatNative(paren token.Token, arguments []interface{}) (interface{}, error) {
+	template, ok := arguments[0].(string)
+	if !ok {
+		return nil, NewRuntimeError(paren, "Argument 'template' of format() must be a string.")
+	}
+	return formatString(paren, template, arguments[1].(*List).Elements)
+}
+
+// formatString substitutes values into the fields of a format string
+func formatString(paren token.Token, s string, values []interface{}) (string, error) {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '}' {
+			if i+1 < len(s) && s[i+1] == '}' {
+				i++
+			}
+			b.WriteByte('}')
+			continue
+		}
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '{' {
+			b.WriteByte('{')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", NewRuntimeError(paren, "Unclosed '{' in format string.")
+		}
+		field := s[i+1 : i+end]
+		i += end
+
+		reference, spec := field, ""
+		if colon := strings.IndexByte(field, ':'); colon >= 0 {
+			reference, spec = field[:colon], field[colon+1:]
+		}
+		index := next
+		if reference != "" {
+			n, err := strconv.Atoi(reference)
+			if err != nil {
+				return "", NewRuntimeError(paren, "Invalid format field '{"+field+"}'.")
+			}
+			index = n
+		} else {
+			next++
+		}
+		if index < 0 || index >= len(values) {
+			return "", NewRuntimeError(paren, "Format field '{"+field+"}' has no value.")
+		}
+
+		text, err := formatField(paren, values[index], spec)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text)
+	}
+	return b.String(), nil
+}
+
+// formatSpec is a parsed field spec. Unset align, sign and kind are 0 and unset precision is -1.
+type formatSpec struct {
+	fill      rune
+	align     rune
+	sign      rune
+	zero      bool
+	width     int
+	grouping  bool
+	precision int
+	kind      rune
+}
+
+// maxFormatWidth bounds the width and precision of a field spec
+const maxFormatWidth = 4096
+
+func parseFormatSpec(paren token.Token, spec string) (formatSpec, error) {
+	f := formatSpec{fill: ' ', precision: -1}
+	runes := []rune(spec)
+	i := 0
+	if len(runes) >= 2 && strings.ContainsRune("<>^", runes[1]) {
+		f.fill, f.align = runes[0], runes[1]
+		i = 2
+	} else if len(runes) >= 1 && strings.ContainsRune("<>^", runes[0]) {
+		f.align = runes[0]
+		i = 1
+	}
+	if i < len(runes) && strings.ContainsRune("+- ", runes[i]) {
+		f.sign = runes[i]
+		i++
+	}
+	if i < len(runes) && runes[i] == '0' {
+		f.zero = true
+		i++
+	}
+	for ; i < len(runes) && runes[i] >= '0' && runes[i] <= '9'; i++ {
+		f.width = f.width*10 + int(runes[i]-'0')
+		if f.width > maxFormatWidth {
+			return f, NewRuntimeError(paren, "Invalid format spec '"+spec+"'.")
+		}
+	}
+	if i < len(runes) && runes[i] == ',' {
+		f.grouping = true
+		i++
+	}
+	if i < len(runes) && runes[i] == '.' {
+		i++
+		start := i
+		f.precision = 0
+		for ; i < len(runes) && runes[i] >= '0' && runes[i] <= '9'; i++ {
+			f.precision = f.precision*10 + int(runes[i]-'0')
+			if f.precision > maxFormatWidth {
+				return f, NewRuntimeError(paren, "Invalid format spec '"+spec+"'.")
+			}
+		}
+		if i == start {
+			return f, NewRuntimeError(paren, "Invalid format spec '"+spec+"'.")
+		}
+	}
+	if i < len(runes) && strings.ContainsRune("dxXobfeg%s", runes[i]) {
+		f.kind = runes[i]
+		i++
+	}
+	if i != len(runes) {
+		return f, NewRuntimeError(paren, "Invalid format spec '"+spec+"'.")
+	}
+	return f, nil
+}
+
+// formatField formats one value by a field spec
+func formatField(paren token.Token, value interface{}, spec string) (string, error) {
+	if spec == "" {
+		return stringify(value), nil
+	}
+	f, err := parseFormatSpec(paren, spec)
+	if err != nil {
+		return "", err
+	}
+
+	kind := f.kind
+	if kind == 0 && isInteger(value) {
+		kind = 'd'
+	} else if kind == 0 && isNumber(value) && f.precision >= 0 {
+		kind = 'f'
+	}
+
+	numeric := isNumber(value) && kind != 's'
+	negative := false
+	var text string
+	switch kind {
+	case 'd', 'x', 'X', 'o', 'b':
+		if !isInteger(value) {
+			return "", NewRuntimeError(paren, "Format type '"+string(kind)+"' requires an integer.")
+		}
+		n := toBigInt(value)
+		negative = n.Sign() < 0
+		bases := map[rune]int{'d': 10, 'x': 16, 'X': 16, 'o': 8, 'b': 2}
+		text = new(big.Int).Abs(n).Text(bases[kind])
+		if kind == 'X' {
+			text = strings.ToUpper(text)
+		}
+	case 'f', 'e', 'g', '%':
+		if !isNumber(value) {
+			return "", NewRuntimeError(paren, "Format type '"+string(kind)+"' requires a number.")
+		}
+		negative, text = formatReal(value, kind, f.precision)
+	case 0:
+		if isNumber(value) {
+			// Untyped floats and decimals without a precision
+			negative = compareNumbers(value, int64(0)) < 0
+			if negative {
+				text = stringify(value)[1:]
+			} else {
+				text = stringify(value)
+			}
+			break
+		}
+		fallthrough
+	default:
+		text = stringify(value)
+		if f.precision >= 0 && utf8.RuneCountInString(text) > f.precision {
+			text = string([]rune(text)[:f.precision])
+		}
+	}
+
+	if numeric && f.grouping {
+		text = groupThousands(text)
+	}
+	prefix := ""
+	if numeric {
+		if negative {
+			prefix = "-"
+		} else if f.sign == '+' || f.sign == ' ' {
+			prefix = string(f.sign)
+		}
+	}
+
+	padding := f.width - utf8.RuneCountInString(prefix+text)
+	if padding <= 0 {
+		return prefix + text, nil
+	}
+	if numeric && f.zero && f.align == 0 {
+		return prefix + strings.Repeat("0", padding) + text, nil
+	}
+
+	align := f.align
+	if align == 0 {
+		align = '<'
+		if numeric {
+			align = '>'
+		}
+	}
+	fill := string(f.fill)
+	switch align {
+	case '<':
+		return prefix + text + strings.Repeat(fill, padding), nil
+	case '^':
+		return strings.Repeat(fill, padding/2) + prefix + text + strings.Repeat(fill, padding-padding/2), nil
+	}
+	return strings.Repeat(fill, padding) + prefix + text, nil
+}
+
+// formatReal formats the magnitude of a number as f, e, g or %, returning whether it is negative
+func formatReal(value interface{}, kind rune, precision int) (bool, string) {
+	if precision < 0 && kind != 'g' {
+		precision = 6
+	}
+
+	// Only floats lose precision, other numbers format exactly as f and %
+	if x, ok := value.(float64); ok || kind == 'e' || kind == 'g' {
+		x = toFloat(value)
+		suffix := ""
+		if kind == '%' {
+			x *= 100
+			kind = 'f'
+			suffix = "%"
+		}
+		if math.IsNaN(x) {
+			return false, "NaN"
+		}
+		if math.IsInf(x, 0) {
+			return x < 0, "Inf"
+		}
+		return x < 0, strconv.FormatFloat(math.Abs(x), byte(kind), precision, 64) + suffix
+	}
+
+	r := new(big.Rat).Set(toDecimal(value))
+	suffix := ""
+	if kind == '%' {
+		r.Mul(r, big.NewRat(100, 1))
+		suffix = "%"
+	}
+	return r.Sign() < 0, new(big.Rat).Abs(r).FloatString(precision) + suffix
+}
+
+// groupThousands separates the integer digits at the start of a number with commas
+func groupThousands(text string) string {
+	digits := strings.IndexFunc(text, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if digits < 0 {
+		digits = len(text)
+	}
+
+	var b strings.Builder
+	for i, c := range text[:digits] {
+		if i > 0 && (digits-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString(text[digits:])
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package expr_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/levi/holo/expr"
+)
+
+func TestPrint(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print 1, "a", nil;`, output: "1 a nil\n"},
+		{source: `print;`, output: "\n"},
+		{source: `print 1, 2, sep: "-";`, output: "1-2\n"},
+		{source: `print 1, 2, end: "!"; print 3;`, output: "1 2!3\n"},
+		{source: `print 1, 2, end: "", sep: ""; print;`, output: "12\n"},
+		{source: `print sep: "-", end: "|";`, output: "|"},
+		{source: `print 1, sep: 2;`, err: "Print option 'sep' must be a string."},
+	})
+}
+
+func TestEprint(t *testing.T) {
+	var errors bytes.Buffer
+	expr.SetErrorOutput(&errors)
+	defer expr.SetErrorOutput(os.Stderr)
+
+	output, err := run(t, `eprint "oops", 1, sep: ": "; print "ok";`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "ok\n" {
+		t.Errorf("printed %q, want %q", output, "ok\n")
+	}
+	if errors.String() != "oops: 1\n" {
+		t.Errorf("eprinted %q, want %q", errors.String(), "oops: 1\n")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print format("{} and {}", 1, "two");`, output: "1 and two\n"},
+		{source: `print format("{1}{0}{}", "a", "b");`, output: "baa\n"},
+		{source: `print format("{{}} {{{}}}", 1);`, output: "{} {1}\n"},
+		{source: `print format("{:>8.2f}|", 3.14159);`, output: "    3.14|\n"},
+		{source: `print format("{:<5}|{:^7}|{:*^6}", "ab", "mid", "hi");`, output: "ab   |  mid  |**hi**\n"},
+		{source: `print format("{:+d} {:05d} {: d}", 5, -42, 7);`, output: "+5 -0042  7\n"},
+		{source: `print format("{:,} {:x} {:X} {:o} {:b}", 1234567, 255, 255, 8, 5);`, output: "1,234,567 ff FF 10 101\n"},
+		{source: `print format("{:.1%} {:.2e} {:.3}", 0.256, 12345.0, "abcdef");`, output: "25.6% 1.23e+04 abc\n"},
+		{source: `print format("{:.2f} {:d}", 1.005d, 12345678901234567890n);`, output: "1.01 12345678901234567890\n"},
+		{source: `print format("{:4096}", 1).len();`, output: "4096\n"},
+		{source: `print "{:>4}".format(7);`, output: "   7\n"},
+		{source: `print format("{", 1);`, err: "Unclosed '{' in format string."},
+		{source: `print format("{} {}", 1);`, err: "Format field '{}' has no value."},
+		{source: `print format("{a}", 1);`, err: "Invalid format field '{a}'."},
+		{source: `print format("{:d}", 1.5);`, err: "Format type 'd' requires an integer."},
+		{source: `print format("{:f}", "x");`, err: "Format type 'f' requires a number."},
+		{source: `print format("{:q}", 1);`, err: "Invalid format spec 'q'."},
+		{source: `print format("{:.}", 1);`, err: "Invalid format spec '.'."},
+		{source: `print format("{:099999999999999999999}", 1);`, err: "Invalid format spec '099999999999999999999'."},
+		{source: `print format("{:4097}", 1);`, err: "Invalid format spec '4097'."},
+		{source: `print format("{:.99999999999999999999}", 1.5);`, err: "Invalid format spec '.99999999999999999999'."},
+		{source: `print format(1);`, err: "Argument 'template' of format() must be a string."},
+	})
+}
@@ -0,0 +1,171 @@
+package expr
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/levi/holo/token"
+)
+
+// The fs and path modules, both working through the configured FileSystem
+func init() {
+	f := DefineNativeModule("fs")
+	f.exportNative("readFile", []string{"path"}, 1, false, fsReadFile)
+	f.exportNative("writeFile", []string{"path", "text"}, 2, false, fsWriteFile)
+	f.exportNative("readLines", []string{"path"}, 1, false, fsReadLines)
+	f.exportNative("exists", []string{"path"}, 1, false, fsExists)
+	f.exportNative("listDir", []string{"path"}, 1, false, fsListDir)
+	f.exportNative("mkdirAll", []string{"path"}, 1, false, fsMkdirAll)
+	f.exportNative("remove", []string{"path"}, 1, false, fsRemove)
+
+	p := DefineNativeModule("path")
+	p.exportNative("join", []string{"parts"}, 0, true, pathJoin)
+	p.exportNative("base", []string{"path"}, 1, false, pathFunction(filepath.Base))
+	p.exportNative("dir", []string{"path"}, 1, false, pathFunction(filepath.Dir))
+	p.exportNative("ext", []string{"path"}, 1, false, pathFunction(filepath.Ext))
+	p.exportNative("abs", []string{"path"}, 1, false, pathAbs)
+}
+
+// fileError reports a failed file system operation
+func fileError(paren token.Token, signature *Signature, err error) error {
+	return NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+}
+
+func fsReadFile(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fs.ReadFile(fileSystem, path)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+	return string(data), nil
+}
+
+func fsWriteFile(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	text, err := stringArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	err = fileSystem.WriteFile(path, []byte(text), 0644)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+	return nil, nil
+}
+
+// fsReadLines lists the lines of a file without their line endings
+func fsReadLines(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fs.ReadFile(fileSystem, path)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+
+	elements := []interface{}{}
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return NewList(elements), nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		elements = append(elements, strings.TrimSuffix(line, "\r"))
+	}
+	return NewList(elements), nil
+}
+
+func fsExists(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fs.Stat(fileSystem, path)
+	return err == nil, nil
+}
+
+// fsListDir lists the names in a directory in sorted order
+func fsListDir(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(fileSystem, path)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+	elements := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		elements[i] = entry.Name()
+	}
+	return NewList(elements), nil
+}
+
+func fsMkdirAll(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	err = fileSystem.MkdirAll(path, 0755)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+	return nil, nil
+}
+
+// fsRemove removes a file or an empty directory
+func fsRemove(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	err = fileSystem.Remove(path)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+	return nil, nil
+}
+
+func pathJoin(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	parts := arguments[0].(*List).Elements
+	elements := make([]string, len(parts))
+	for i, part := range parts {
+		s, ok := part.(string)
+		if !ok {
+			return nil, argumentError(paren, signature, 0, "strings")
+		}
+		elements[i] = s
+	}
+	return filepath.Join(elements...), nil
+}
+
+// pathFunction adapts a function of one path to a native
+func pathFunction(f func(string) string) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		path, err := stringArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		return f(path), nil
+	}
+}
+
+func pathAbs(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	path, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := fileSystem.Abs(path)
+	if err != nil {
+		return nil, fileError(paren, signature, err)
+	}
+	return abs, nil
+}
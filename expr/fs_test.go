@@ -0,0 +1,84 @@
+package expr_test
+
+import (
+	"io/fs"
+	"path"
+	"testing"
+	"testing/fstest"
+
+	"github.com/levi/holo/expr"
+)
+
+// runFileScripts runs each script against a fresh in-memory file system holding files
+func runFileScripts(t *testing.T, files map[string]string, tests []scriptTest) {
+	t.Helper()
+	defer expr.SetFileSystem(expr.OSFileSystem{})
+	for _, test := range tests {
+		fsys := expr.NewMemoryFileSystem()
+		for name, text := range files {
+			if err := fsys.MkdirAll(path.Dir(name), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsys.WriteFile(name, []byte(text), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expr.SetFileSystem(fsys)
+		runScripts(t, []scriptTest{test})
+	}
+}
+
+func TestFSModule(t *testing.T) {
+	files := map[string]string{
+		"data/a.txt":    "one\r\ntwo\n",
+		"data/b.txt":    "",
+		"data/sub/c.md": "c",
+	}
+	runFileScripts(t, files, []scriptTest{
+		{source: `print fs.readFile("data/sub/c.md"); print fs.readFile("/data/sub/../sub/c.md"); print fs.readFile("data/b.txt") + "|";`, output: "c\nc\n|\n"},
+		{source: `print fs.readLines("data/a.txt"); print fs.readLines("/data/b.txt"); print fs.readLines("data/sub/c.md");`, output: "[\"one\", \"two\"]\n[]\n[\"c\"]\n"},
+		{source: `print fs.exists("data/a.txt"); print fs.exists("data"); print fs.exists("./data/../data/b.txt"); print fs.exists("nope");`, output: "true\ntrue\ntrue\nfalse\n"},
+		{source: `print fs.listDir("data"); print fs.listDir("/"); print fs.listDir(".");`, output: "[\"a.txt\", \"b.txt\", \"sub\"]\n[\"data\"]\n[\"data\"]\n"},
+		{source: `fs.writeFile("data/new.txt", "hi"); print fs.readFile("data/new.txt"); print fs.listDir("data");`, output: "hi\n[\"a.txt\", \"b.txt\", \"new.txt\", \"sub\"]\n"},
+		{source: `fs.writeFile("data/a.txt", "replaced"); print fs.readFile("data/a.txt");`, output: "replaced\n"},
+		{source: `fs.mkdirAll("x/y/z"); fs.mkdirAll("x/y"); print fs.listDir("x"); print fs.listDir("x/y/z");`, output: "[\"y\"]\n[]\n"},
+		{source: `fs.remove("data/b.txt"); fs.mkdirAll("e"); fs.remove("e"); print fs.exists("data/b.txt"); print fs.exists("e");`, output: "false\nfalse\n"},
+		{source: `print path.abs("data/a.txt"); print path.abs("/x/../y"); print path.abs(".");`, output: "/data/a.txt\n/y\n/\n"},
+		{source: `print path.join("a", "b", "../c.txt"); print path.base("a/b.txt"); print path.dir("a/b.txt"); print path.ext("a/b.txt");`, output: "a/c.txt\nb.txt\na\n.txt\n"},
+		{source: `fs.readFile("nope.txt");`, err: "fs.readFile() failed: open nope.txt: file does not exist."},
+		{source: `fs.readFile("data");`, err: "fs.readFile() failed: read data: is a directory."},
+		{source: `fs.readFile("../secret");`, err: "fs.readFile() failed: open ../secret: invalid argument."},
+		{source: `fs.listDir("data/a.txt");`, err: "fs.listDir() failed: readdir data/a.txt: not a directory."},
+		{source: `fs.writeFile("missing/x.txt", "");`, err: "fs.writeFile() failed: open missing/x.txt: file does not exist."},
+		{source: `fs.writeFile("data", "");`, err: "fs.writeFile() failed: open data: is a directory."},
+		{source: `fs.writeFile("data/a.txt/x", "");`, err: "fs.writeFile() failed: open data/a.txt/x: not a directory."},
+		{source: `fs.mkdirAll("data/a.txt/x");`, err: "fs.mkdirAll() failed: mkdir data/a.txt/x: not a directory."},
+		{source: `fs.remove("data");`, err: "fs.remove() failed: remove data: directory not empty."},
+		{source: `fs.remove("nope");`, err: "fs.remove() failed: remove nope: file does not exist."},
+		{source: `fs.writeFile("a.txt", 1);`, err: "Argument 'text' of fs.writeFile() must be a string."},
+	})
+}
+
+func TestMemoryFileSystem(t *testing.T) {
+	fsys := expr.NewMemoryFileSystem()
+	if err := fsys.MkdirAll("data/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.MkdirAll("data/empty", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"data/a.txt", "data/sub/c.md"} {
+		if err := fsys.WriteFile(name, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The file system accepts script paths such as "/data" or "data/../data", so it's checked beneath fs.Sub
+	// which only passes on the names fs.ValidPath allows
+	sub, err := fs.Sub(fsys, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(sub, "a.txt", "sub/c.md", "empty"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,268 @@
+package expr
+
+import (
+	"fmt"
+
+	"github.com/levi/holo/token"
+)
+
+// maxCallDepth bounds recursion so runaway scripts fail with a runtime error rather than exhausting the Go stack
+const maxCallDepth = 10000
+
+// Callable is a value which can be called, such as a user-defined function or a native builtin
+type Callable interface {
+	Signature() *Signature
+	Call(paren token.Token, arguments []interface{}) (interface{}, error)
+}
+
+// Signature describes the parameters of a callable. The first Required parameters must be given,
+// the rest are optional. When Variadic is set, the last parameter collects any remaining positional
+// arguments into a list.
+type Signature struct {
+	Name     string
+	Params   []string
+	Required int
+	Variadic bool
+}
+
+// NewSignature allocates a signature
+func NewSignature(name string, params []string, required int, variadic bool) *Signature {
+	return &Signature{
+		name,
+		params,
+		required,
+		variadic,
+	}
+}
+
+// arity describes the number of arguments accepted, such as "1 argument", "1 to 2 arguments" or "at least 1 argument"
+func (s *Signature) arity() string {
+	max := len(s.Params)
+	if s.Variadic {
+		return fmt.Sprintf("at least %s", pluralize(s.Required, "argument"))
+	}
+	if s.Required == max {
+		return pluralize(max, "argument")
+	}
+	return fmt.Sprintf("%d to %s", s.Required, pluralize(max, "argument"))
+}
+
+// pluralize counts n of a noun, such as "1 argument" or "2 arguments"
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// omitted marks an optional parameter for which no argument was given
+type omitted struct{}
+
+// defaulted reports whether an optional argument was omitted or given as nil, so a native should use its default
+func defaulted(arguments []interface{}, index int) bool {
+	switch arguments[index].(type) {
+	case nil, omitted:
+		return true
+	}
+	return false
+}
+
+// bindArguments matches positional and named arguments to the parameters of a signature, producing
+// one argument per parameter. Omitted optional parameters hold omitted{} and a variadic parameter
+// holds a list of the remaining positional arguments.
+func bindArguments(paren token.Token, signature *Signature, positional []interface{}, names []string) ([]interface{}, error) {
+	fixed := len(signature.Params)
+	if signature.Variadic {
+		fixed--
+	}
+
+	arguments := make([]interface{}, len(signature.Params))
+	bound := make([]bool, len(signature.Params))
+	count := 0
+	var rest []interface{}
+	for i, value := range positional {
+		if names[i] != "" {
+			continue
+		}
+		count++
+		if count <= fixed {
+			arguments[count-1] = value
+			bound[count-1] = true
+		} else if signature.Variadic {
+			rest = append(rest, value)
+		}
+	}
+	if count > fixed && !signature.Variadic {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("Expected %s but got %d.", signature.arity(), count))
+	}
+
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+		index := -1
+		for j := 0; j < fixed; j++ {
+			if signature.Params[j] == name {
+				index = j
+			}
+		}
+		if index < 0 {
+			return nil, NewRuntimeError(paren, "Unknown parameter '"+name+"' for "+signature.Name+"().")
+		}
+		if bound[index] {
+			return nil, NewRuntimeError(paren, "Argument '"+name+"' given more than once.")
+		}
+		arguments[index] = positional[i]
+		bound[index] = true
+	}
+
+	for i := 0; i < fixed; i++ {
+		if bound[i] {
+			continue
+		}
+		if i < signature.Required {
+			if count == len(positional) {
+				return nil, NewRuntimeError(paren, fmt.Sprintf("Expected %s but got %d.", signature.arity(), count))
+			}
+			return nil, NewRuntimeError(paren, "Missing argument '"+signature.Params[i]+"' for "+signature.Name+"().")
+		}
+		arguments[i] = omitted{}
+	}
+
+	if signature.Variadic {
+		arguments[fixed] = NewList(rest)
+	}
+	return arguments, nil
+}
+
+// frame records an active call for stack traces
+type frame struct {
+	name string
+	line int
+}
+
+// callStack holds the active calls, innermost last
+var callStack []frame
+
+// call binds arguments to a callable and invokes it, tracking the call for stack traces
+func call(paren token.Token, callable Callable, values []interface{}, names []string) (interface{}, error) {
+	if len(callStack) >= maxCallDepth {
+		return nil, NewRuntimeError(paren, "Stack overflow.")
+	}
+	arguments, err := bindArguments(paren, callable.Signature(), values, names)
+	if err != nil {
+		return nil, err
+	}
+
+	callStack = append(callStack, frame{callable.Signature().Name, paren.Line})
+	defer func() {
+		callStack = callStack[:len(callStack)-1]
+	}()
+	return callable.Call(paren, arguments)
+}
+
+// stackTrace describes the active calls for an error raised at line, innermost first
+func stackTrace(line int) []string {
+	trace := []string{}
+	for i := len(callStack) - 1; i >= 0; i-- {
+		trace = append(trace, fmt.Sprintf("[line %d] in %s()", line, callStack[i].name))
+		line = callStack[i].line
+	}
+	return append(trace, fmt.Sprintf("[line %d] in script", line))
+}
+
+// returnSignal unwinds execution to the enclosing function call for return statements
+type returnSignal struct {
+	value interface{}
+}
+
+func (r *returnSignal) Error() string {
+	return "Can't return from top-level code."
+}
+
+// Function is a user-defined function, closing over the environment it was declared in
+type Function struct {
+	declaration Fn
+	closure     *Environment
+	signature   *Signature
+}
+
+// NewFunction allocates a function for a declaration evaluated within closure
+func NewFunction(declaration Fn, closure *Environment) *Function {
+	f := new(Function)
+	f.declaration = declaration
+	f.closure = closure
+
+	params := make([]string, len(declaration.params))
+	required := 0
+	for i, param := range declaration.params {
+		params[i] = param.Lexeme
+		if declaration.defaults[i] == nil && !(declaration.variadic && i == len(params)-1) {
+			required++
+		}
+	}
+	f.signature = NewSignature(declaration.name.Lexeme, params, required, declaration.variadic)
+	return f
+}
+
+func (f *Function) Signature() *Signature {
+	return f.signature
+}
+
+func (f *Function) Call(paren token.Token, arguments []interface{}) (interface{}, error) {
+	env := NewEnvironment(f.closure)
+	for i, param := range f.declaration.params {
+		value := arguments[i]
+		if _, ok := value.(omitted); ok {
+			// Defaults are evaluated at call time and can refer to earlier parameters
+			var err error
+			value, err = evaluateIn(f.declaration.defaults[i], env)
+			if err != nil {
+				return nil, err
+			}
+		}
+		env.Define(param.Lexeme, value)
+	}
+
+	err := executeBlock(f.declaration.body, env)
+	if r, ok := err.(*returnSignal); ok {
+		return r.value, nil
+	}
+	return nil, err
+}
+
+func (f *Function) String() string {
+	return "<fn " + f.signature.Name + ">"
+}
+
+// Native is a builtin function implemented in Go
+type Native struct {
+	signature *Signature
+	function  func(paren token.Token, arguments []interface{}) (interface{}, error)
+}
+
+// NewNative allocates a builtin function. Omitted optional arguments are passed to function as omitted{},
+// distinguishing them from an explicit nil.
+func NewNative(signature *Signature, function func(paren token.Token, arguments []interface{}) (interface{}, error)) *Native {
+	n := new(Native)
+	n.signature = signature
+	n.function = function
+	return n
+}
+
+func (n *Native) Signature() *Signature {
+	return n.signature
+}
+
+func (n *Native) Call(paren token.Token, arguments []interface{}) (interface{}, error) {
+	return n.function(paren, arguments)
+}
+
+func (n *Native) String() string {
+	return "<native fn " + n.signature.Name + ">"
+}
+
+// DefineNative registers a builtin function, visible from every module
+func DefineNative(native *Native) {
+	builtins.Define(native.signature.Name, native)
+}
@@ -0,0 +1,40 @@
+package expr_test
+
+import "testing"
+
+func TestFunctionArguments(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `fn greet(name, greeting = "hi") { return greeting + " " + name; } print greet("x");`, output: "hi x\n"},
+		{source: `fn greet(name, greeting = "hi") { return greeting + " " + name; } print greet("x", "yo");`, output: "yo x\n"},
+		{source: `fn greet(name, greeting = "hi") { return greeting + " " + name; } print greet(greeting: "yo", name: "x");`, output: "yo x\n"},
+		{source: "fn f(a, b = a * 2) { return b; } print f(3);", output: "6\n"},
+		{source: "fn f(a = nil) { return a ?? 1; } print f(nil); print f();", output: "1\n1\n"},
+		{source: "fn f(...nums) { return nums; } print f(); print f(1, 2);", output: "[]\n[1, 2]\n"},
+		{source: "fn f(a, ...rest) { return rest; } print f(1, 2, 3);", output: "[2, 3]\n"},
+		{source: "fn f(a) {} f();", err: "Expected 1 argument but got 0."},
+		{source: "fn f(a) {} f(1, 2);", err: "Expected 1 argument but got 2."},
+		{source: "fn f() {} f(1);", err: "Expected 0 arguments but got 1."},
+		{source: "fn f(a, b) {} f(1);", err: "Expected 2 arguments but got 1."},
+		{source: "fn f(a, b = 1) {} f(1, 2, 3);", err: "Expected 1 to 2 arguments but got 3."},
+		{source: "fn f(a, ...rest) {} f();", err: "Expected at least 1 argument but got 0."},
+		{source: "fn f(a, b, ...rest) {} f();", err: "Expected at least 2 arguments but got 0."},
+		// Named arguments don't count towards the positional arguments reported
+		{source: "fn f(a, b = 1) {} f(1, 2, b: 3);", err: "Argument 'b' given more than once."},
+		{source: "fn f(a) {} f(1, 2, a: 3);", err: "Expected 1 argument but got 2."},
+		{source: "fn f(a, b) {} f(b: 1);", err: "Missing argument 'a' for f()."},
+		{source: "fn f(a) {} f(c: 1);", err: "Unknown parameter 'c' for f()."},
+		{source: "fn f(...rest) {} f(rest: 1);", err: "Unknown parameter 'rest' for f()."},
+	})
+}
+
+func TestNativeArguments(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print freeze();", err: "Expected 1 argument but got 0."},
+		{source: "print sort([1], nil, 2);", err: "Expected 1 to 2 arguments but got 3."},
+		{source: "print sort([2, 1], compare: nil);", output: "[1, 2]\n"},
+		{source: "fn add(a, b) { return a + b; } print reduce([1, 2], add);", output: "3\n"},
+		{source: "fn add(a, b) { return a + b; } print reduce([1, 2], add, 10);", output: "13\n"},
+		{source: "fn f(a, b) { return b; } print reduce([], f);", err: "Cannot reduce an empty iterable without an initial value."},
+		{source: "print sum([1, 2], nil);", output: "3\n"},
+	})
+}
@@ -2,6 +2,8 @@ package expr
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/levi/holo/token"
 )
@@ -10,9 +12,17 @@ type Interpreter interface {
 	ToValue() (interface{}, error)
 }
 
+// builtins encloses the global environment of every module, environment is the innermost scope being executed
+var builtins = NewEnvironment(nil)
+var environment = mainModule.globals
+
 func Interpret(statements []Stmt) error {
 	for _, s := range statements {
 		err := execute(s)
+		if thrown, ok := err.(*Thrown); ok {
+			// Uncaught exceptions are reported like any other runtime error, with the stack of the throw
+			return &RuntimeError{thrown.Token, thrown.Error(), thrown.Stack}
+		}
 		if err != nil {
 			return err
 		}
@@ -25,73 +35,637 @@ func execute(statement Stmt) error {
 	return err
 }
 
+// executeBlock executes statements within env, restoring the current environment afterwards
+func executeBlock(statements []Stmt, env *Environment) error {
+	previous := environment
+	environment = env
+	defer func() {
+		environment = previous
+	}()
+
+	for _, s := range statements {
+		err := execute(s)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loopSignal unwinds execution to the enclosing loop for break and continue statements.
+// An empty label targets the innermost loop.
+type loopSignal struct {
+	keyword token.Token
+	label   string
+}
+
+func (s *loopSignal) Error() string {
+	return "Can't use '" + s.keyword.Lexeme + "' outside of a loop."
+}
+
+// loopExit decides how a loop named label proceeds after its body returned err,
+// reporting whether the loop should stop and the error it should propagate
+func loopExit(err error, label string) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+	signal, ok := err.(*loopSignal)
+	if !ok || (signal.label != "" && signal.label != label) {
+		return true, err
+	}
+	return signal.keyword.TokenType == token.BreakToken, nil
+}
+
 func stringify(value interface{}) string {
-	if value == nil {
+	return format(value, false, make(map[interface{}]bool))
+}
+
+// format renders a value for display, quoting strings nested within collections and eliding collections that contain themselves
+func format(value interface{}, quoted bool, seen map[interface{}]bool) string {
+	switch v := value.(type) {
+	case nil:
 		return "nil"
+	case string:
+		if quoted {
+			return strconv.Quote(v)
+		}
+		return v
+	case float64:
+		return formatFloat(v)
+	case *Decimal:
+		return formatDecimal(v)
+	case *List:
+		if seen[v] {
+			return "[...]"
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		elements := make([]string, len(v.Elements))
+		for i, element := range v.Elements {
+			elements[i] = format(element, true, seen)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *Map:
+		if seen[v] {
+			return "{...}"
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		entries := make([]string, v.Len())
+		for i, entry := range v.Entries() {
+			entries[i] = format(entry.Key, true, seen) + ": " + format(entry.Value, true, seen)
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	case *Range:
+		return fmt.Sprintf("%d..%d", v.Start, v.End)
 	}
 	return fmt.Sprintf("%v", value)
 }
 
+func (b Block) ToValue() (interface{}, error) {
+	return nil, executeBlock(b.statements, NewEnvironment(environment))
+}
+
+func (f ForIn) ToValue() (interface{}, error) {
+	iterable, err := evaluate(f.iterable)
+	if err != nil {
+		return nil, err
+	}
+	iterator, err := iterate(f.keyword, iterable, len(f.names) == 2)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		value, ok, err := iterator.Next()
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		// Each iteration binds its variables in a fresh environment
+		env := NewEnvironment(environment)
+		if len(f.names) == 2 {
+			pair := value.(*List)
+			env.Define(f.names[0].Lexeme, pair.Elements[0])
+			env.Define(f.names[1].Lexeme, pair.Elements[1])
+		} else {
+			env.Define(f.names[0].Lexeme, value)
+		}
+
+		stop, err := loopExit(executeBlock([]Stmt{f.body}, env), f.label)
+		if stop {
+			return nil, err
+		}
+	}
+}
+
+func (w While) ToValue() (interface{}, error) {
+	for {
+		condition, err := evaluate(w.condition)
+		if err != nil {
+			return nil, err
+		}
+		if !isTruthy(condition) {
+			return nil, nil
+		}
+
+		stop, err := loopExit(execute(w.body), w.label)
+		if stop {
+			return nil, err
+		}
+	}
+}
+
+func (b Break) ToValue() (interface{}, error) {
+	return nil, &loopSignal{b.keyword, b.label}
+}
+
+func (c Continue) ToValue() (interface{}, error) {
+	return nil, &loopSignal{c.keyword, c.label}
+}
+
+func (f Fn) ToValue() (interface{}, error) {
+	return nil, environment.Declare(f.name, NewFunction(f, environment), false)
+}
+
+func (r Return) ToValue() (interface{}, error) {
+	var value interface{}
+	if r.value != nil {
+		var err error
+		value, err = evaluate(r.value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, &returnSignal{value}
+}
+
+func (i Import) ToValue() (interface{}, error) {
+	if loader == nil {
+		return nil, NewRuntimeError(i.keyword, "Imports are not supported.")
+	}
+	module, err := loader.Load(i.keyword, currentModule, i.path)
+	if err != nil {
+		return nil, err
+	}
+	return nil, environment.Declare(i.name, module, false)
+}
+
+func (e Export) ToValue() (interface{}, error) {
+	err := execute(e.declaration)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range declaredNames(e.declaration) {
+		currentModule.exports[name] = true
+	}
+	return nil, nil
+}
+
+func (t Throw) ToValue() (interface{}, error) {
+	value, err := evaluate(t.value)
+	if err != nil {
+		return nil, err
+	}
+	return nil, NewThrown(t.keyword, value)
+}
+
+func (t Try) ToValue() (interface{}, error) {
+	err := executeBlock(t.body, NewEnvironment(environment))
+	if err != nil && t.catchBody != nil {
+		if value, ok := caught(err); ok {
+			env := NewEnvironment(environment)
+			env.Define(t.name.Lexeme, value)
+			err = executeBlock(t.catchBody, env)
+		}
+	}
+
+	if t.finallyBody != nil {
+		// An error raised by the finally block replaces any pending error, except an exit which can't be
+		// cancelled by returning or throwing
+		finallyErr := executeBlock(t.finallyBody, NewEnvironment(environment))
+		if _, exiting := err.(*Exit); finallyErr != nil && !exiting {
+			return nil, finallyErr
+		}
+	}
+	return nil, err
+}
+
+func (i If) ToValue() (interface{}, error) {
+	condition, err := evaluate(i.condition)
+	if err != nil {
+		return nil, err
+	}
+	if isTruthy(condition) {
+		return nil, execute(i.thenBranch)
+	}
+	if i.elseBranch != nil {
+		return nil, execute(i.elseBranch)
+	}
+	return nil, nil
+}
+
 func (e Expression) ToValue() (interface{}, error) {
 	_, err := evaluate(e.expression)
 	return nil, err
 }
 
 func (p Print) ToValue() (interface{}, error) {
-	value, err := evaluate(p.expression)
+	values := make([]string, len(p.expressions))
+	for i, expression := range p.expressions {
+		value, err := evaluate(expression)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = stringify(value)
+	}
+	sep, err := p.option(p.sep, "sep", " ")
+	if err != nil {
+		return nil, err
+	}
+	end, err := p.option(p.end, "end", "\n")
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println(stringify(value))
+
+	w := output
+	if p.keyword.TokenType == token.EprintToken {
+		w = errorOutput
+	}
+	fmt.Fprint(w, strings.Join(values, sep)+end)
 	return nil, nil
 }
 
-func (l Literal) ToValue() (interface{}, error) {
-	return l.value, nil
+// option evaluates a string option of a print statement, if given
+func (p Print) option(expression Expr, name, fallback string) (string, error) {
+	if expression == nil {
+		return fallback, nil
+	}
+	value, err := evaluate(expression)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", NewRuntimeError(p.keyword, "Print option '"+name+"' must be a string.")
+	}
+	return s, nil
 }
 
-func (g Grouping) ToValue() (interface{}, error) {
-	return evaluate(g.expression)
+func (v Var) ToValue() (interface{}, error) {
+	var value interface{}
+	if v.initializer != nil {
+		var err error
+		value, err = evaluate(v.initializer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, environment.Declare(v.name, value, false)
 }
 
-func (b Binary) ToValue() (interface{}, error) {
-	left, err := evaluate(b.left)
-	right, err := evaluate(b.right)
+func (v VarPattern) ToValue() (interface{}, error) {
+	value, err := evaluate(v.initializer)
+	if err != nil {
+		return nil, err
+	}
+	bindings := make(map[string]interface{})
+	err = destructure(v.pattern, value, bindings)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range patternBindings(v.pattern) {
+		err = environment.Declare(name, bindings[name.Lexeme], false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (a AssignList) ToValue() (interface{}, error) {
+	value, err := evaluate(a.value)
+	if err != nil {
+		return nil, err
+	}
+	err = assignTarget(a.bracket, NewListLiteral(a.bracket, a.targets), value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c Const) ToValue() (interface{}, error) {
+	value, err := evaluate(c.initializer)
+	if err != nil {
+		return nil, err
+	}
+	return nil, environment.Declare(c.name, value, true)
+}
+
+func (a Assign) ToValue() (interface{}, error) {
+	value, err := evaluate(a.value)
+	if err != nil {
+		return nil, err
+	}
+	if a.operation.TokenType != token.EqualToken {
+		current, err := environment.Get(a.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err = compound(a.operation, current, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = environment.Assign(a.name, value)
 	if err != nil {
 		return nil, err
 	}
+	return value, nil
+}
+
+func (i Increment) ToValue() (interface{}, error) {
+	var current, updated interface{}
+	var err error
 
-	switch b.operation.TokenType {
-	case token.GreaterToken:
-		err := checkNumberOperands(b.operation, left, right)
+	switch target := i.target.(type) {
+	case Variable:
+		current, err = environment.Get(target.name)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) > right.(float64), nil
-	case token.GreaterEqualToken:
-		err := checkNumberOperands(b.operation, left, right)
+		updated, err = step(i.operation, current)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) >= right.(float64), nil
-	case token.LessToken:
-		err := checkNumberOperands(b.operation, left, right)
+		err = environment.Assign(target.name, updated)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) < right.(float64), nil
-	case token.LessEqualToken:
-		err := checkNumberOperands(b.operation, left, right)
+	case Index:
+		object, err := evaluate(target.object)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) <= right.(float64), nil
-	case token.PlusToken:
-		fLeft, lOk := left.(float64)
-		fRight, rOk := right.(float64)
+		index, err := evaluate(target.index)
+		if err != nil {
+			return nil, err
+		}
+		current, err = getIndex(target.bracket, object, index)
+		if err != nil {
+			return nil, err
+		}
+		updated, err = step(i.operation, current)
+		if err != nil {
+			return nil, err
+		}
+		err = setIndex(target.bracket, object, index, updated)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, NewRuntimeError(i.operation, "Invalid assignment target.")
+	}
 
-		if lOk && rOk {
-			return fLeft + fRight, nil
+	if i.prefix {
+		return updated, nil
+	}
+	return current, nil
+}
+
+func (l ListLiteral) ToValue() (interface{}, error) {
+	elements := make([]interface{}, len(l.elements))
+	for i, element := range l.elements {
+		value, err := evaluate(element)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = value
+	}
+	return NewList(elements), nil
+}
+
+func (m MapLiteral) ToValue() (interface{}, error) {
+	result := NewMap()
+	for i, k := range m.keys {
+		key, err := evaluate(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := evaluate(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		if !result.Set(key, value) {
+			return nil, NewRuntimeError(m.brace, "Map keys must be strings, numbers, bools or nil.")
+		}
+	}
+	return result, nil
+}
+
+func (r RangeLiteral) ToValue() (interface{}, error) {
+	start, err := evaluate(r.start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := evaluate(r.end)
+	if err != nil {
+		return nil, err
+	}
+
+	s, sOk := start.(int64)
+	e, eOk := end.(int64)
+	if !sOk || !eOk {
+		return nil, NewRuntimeError(r.operation, "Range bounds must be integers.")
+	}
+	return NewRange(s, e), nil
+}
+
+func (g Get) ToValue() (interface{}, error) {
+	object, err := evaluate(g.object)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o := object.(type) {
+	case *ErrorObject:
+		return o.property(g.name)
+	case *Module:
+		return o.property(g.name)
+	case string:
+		return stringProperty(o, g.name)
+	case Time:
+		return timeProperty(o, g.name)
+	case Duration:
+		return durationProperty(o, g.name)
+	case *Regex:
+		return regexProperty(o, g.name)
+	}
+	return nil, NewRuntimeError(g.name, "Only objects have properties.")
+}
+
+func (m Match) ToValue() (interface{}, error) {
+	subject, err := evaluate(m.subject)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, pattern := range m.patterns {
+		bindings := make(map[string]interface{})
+		ok, err := matches(pattern, subject, bindings)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		env := NewEnvironment(environment)
+		for name, value := range bindings {
+			env.Define(name, value)
+		}
+
+		if m.guards[i] != nil {
+			guard, err := evaluateIn(m.guards[i], env)
+			if err != nil {
+				return nil, err
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		return evaluateIn(m.bodies[i], env)
+	}
+
+	return nil, NewRuntimeError(m.keyword, "No match for value "+format(subject, true, make(map[interface{}]bool))+".")
+}
+
+func (i Index) ToValue() (interface{}, error) {
+	object, err := evaluate(i.object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := evaluate(i.index)
+	if err != nil {
+		return nil, err
+	}
+	return getIndex(i.bracket, object, index)
+}
+
+func (s IndexSet) ToValue() (interface{}, error) {
+	object, err := evaluate(s.object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := evaluate(s.index)
+	if err != nil {
+		return nil, err
+	}
+	value, err := evaluate(s.value)
+	if err != nil {
+		return nil, err
+	}
+	if s.operation.TokenType != token.EqualToken {
+		current, err := getIndex(s.bracket, object, index)
+		if err != nil {
+			return nil, err
+		}
+		value, err = compound(s.operation, current, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = setIndex(s.bracket, object, index, value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s Slice) ToValue() (interface{}, error) {
+	object, err := evaluate(s.object)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := object.(*List)
+	if !ok {
+		return nil, NewRuntimeError(s.bracket, "Only lists can be sliced.")
+	}
+
+	var start, end interface{}
+	if s.start != nil {
+		start, err = evaluate(s.start)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.end != nil {
+		end, err = evaluate(s.end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	n := len(list.Elements)
+	from, err := sliceBound(s.bracket, start, n, 0)
+	if err != nil {
+		return nil, err
+	}
+	to, err := sliceBound(s.bracket, end, n, n)
+	if err != nil {
+		return nil, err
+	}
+	if to < from {
+		to = from
+	}
+
+	elements := make([]interface{}, to-from)
+	copy(elements, list.Elements[from:to])
+	return NewList(elements), nil
+}
+
+func (v Variable) ToValue() (interface{}, error) {
+	return environment.Get(v.name)
+}
+
+func (l Literal) ToValue() (interface{}, error) {
+	return l.value, nil
+}
+
+func (g Grouping) ToValue() (interface{}, error) {
+	return evaluate(g.expression)
+}
+
+func (b Binary) ToValue() (interface{}, error) {
+	left, err := evaluate(b.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := evaluate(b.right)
+	if err != nil {
+		return nil, err
+	}
+	return binaryOperation(b.operation, left, right)
+}
+
+func binaryOperation(operator token.Token, left, right interface{}) (interface{}, error) {
+	if (isTemporal(left) || isTemporal(right)) && operator.TokenType != token.InToken {
+		return timeOperation(operator, left, right)
+	}
+
+	switch operator.TokenType {
+	case token.GreaterToken, token.GreaterEqualToken, token.LessToken, token.LessEqualToken:
+		err := checkNumberOperands(operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+		return compare(operator, left, right)
+	case token.PlusToken:
+		if isNumber(left) && isNumber(right) {
+			return arithmetic(operator, left, right)
 		}
 
 		sLeft, lOk := left.(string)
@@ -101,32 +675,77 @@ func (b Binary) ToValue() (interface{}, error) {
 			return sLeft + sRight, nil
 		}
 
-		return nil, NewRuntimeError(b.operation, "Operands must be two numbers or two strings.")
-	case token.MinusToken:
-		err := checkNumberOperands(b.operation, left, right)
+		return nil, NewRuntimeError(operator, "Operands must be two numbers or two strings.")
+	case token.MinusToken, token.SlashToken, token.StarToken, token.SlashSlashToken, token.PercentToken, token.StarStarToken:
+		err := checkNumberOperands(operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) - right.(float64), nil
-	case token.SlashToken:
-		err := checkNumberOperands(b.operation, left, right)
+		return arithmetic(operator, left, right)
+	case token.AmpersandToken, token.PipeToken, token.CaretToken, token.LessLessToken, token.GreaterGreaterToken:
+		err := checkIntegerOperands(operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) / right.(float64), nil
-	case token.StarToken:
-		err := checkNumberOperands(b.operation, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return left.(float64) * right.(float64), nil
+		return bitwise(operator, left, right)
+	case token.InToken:
+		return contains(operator, left, right)
 	case token.BangEqualToken:
 		return !isEqual(left, right), nil
 	case token.EqualEqualToken:
 		return isEqual(left, right), nil
 	}
 
-	return nil, NewRuntimeError(b.operation, "Undefined operator.")
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+func (c Call) ToValue() (interface{}, error) {
+	callee, err := evaluate(c.callee)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([]interface{}, len(c.arguments))
+	for i, argument := range c.arguments {
+		arguments[i], err = evaluate(argument)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	callable, ok := callee.(Callable)
+	if !ok {
+		return nil, NewRuntimeError(c.paren, "Can only call functions.")
+	}
+	return call(c.paren, callable, arguments, c.names)
+}
+
+func (c Conditional) ToValue() (interface{}, error) {
+	condition, err := evaluate(c.condition)
+	if err != nil {
+		return nil, err
+	}
+	if isTruthy(condition) {
+		return evaluate(c.thenBranch)
+	}
+	return evaluate(c.elseBranch)
+}
+
+func (l Logical) ToValue() (interface{}, error) {
+	left, err := evaluate(l.left)
+	if err != nil {
+		return nil, err
+	}
+
+	switch l.operation.TokenType {
+	case token.QuestionQuestionToken:
+		if left != nil {
+			return left, nil
+		}
+		return evaluate(l.right)
+	}
+
+	return nil, NewRuntimeError(l.operation, "Undefined operator.")
 }
 
 func (u Unary) ToValue() (interface{}, error) {
@@ -143,16 +762,97 @@ func (u Unary) ToValue() (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-		return -(right.(float64)), nil
+		return negate(u.operation, right)
+	case token.TildeToken:
+		err := checkIntegerOperand(u.operation, right)
+		if err != nil {
+			return nil, err
+		}
+		return complement(u.operation, right)
 	}
 
 	return nil, NewRuntimeError(u.operation, "Undefined operator.")
 }
 
+func getIndex(bracket token.Token, object, index interface{}) (interface{}, error) {
+	switch o := object.(type) {
+	case *List:
+		i, err := listIndex(bracket, index, len(o.Elements))
+		if err != nil {
+			return nil, err
+		}
+		return o.Elements[i], nil
+	case *Map:
+		if !isHashable(index) {
+			return nil, NewRuntimeError(bracket, "Map keys must be strings, numbers, bools or nil.")
+		}
+		value, ok := o.Get(index)
+		if !ok {
+			return nil, NewRuntimeError(bracket, "Undefined key "+format(index, true, nil)+".")
+		}
+		return value, nil
+	}
+	return nil, NewRuntimeError(bracket, "Only lists and maps can be indexed.")
+}
+
+func setIndex(bracket token.Token, object, index, value interface{}) error {
+	switch o := object.(type) {
+	case *List:
+		if o.Frozen {
+			return NewRuntimeError(bracket, "Cannot modify a frozen list.")
+		}
+		i, err := listIndex(bracket, index, len(o.Elements))
+		if err != nil {
+			return err
+		}
+		o.Elements[i] = value
+		return nil
+	case *Map:
+		if o.Frozen {
+			return NewRuntimeError(bracket, "Cannot modify a frozen map.")
+		}
+		if !o.Set(index, value) {
+			return NewRuntimeError(bracket, "Map keys must be strings, numbers, bools or nil.")
+		}
+		return nil
+	}
+	return NewRuntimeError(bracket, "Only lists and maps can be indexed.")
+}
+
+// contains implements the in operator, checking for a key in a map or an element in a list or range
+func contains(operator token.Token, item, container interface{}) (interface{}, error) {
+	switch c := container.(type) {
+	case *Map:
+		return c.Has(item), nil
+	case *List:
+		for _, element := range c.Elements {
+			if isEqual(item, element) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case *Range:
+		i, ok := item.(int64)
+		return ok && i >= c.Start && i < c.End, nil
+	}
+	return nil, NewRuntimeError(operator, "Right operand of 'in' must be a list, map or range.")
+}
+
 func evaluate(e Expr) (interface{}, error) {
 	return e.(Interpreter).ToValue()
 }
 
+// evaluateIn evaluates an expression within env, restoring the current environment afterwards
+func evaluateIn(e Expr, env *Environment) (interface{}, error) {
+	previous := environment
+	environment = env
+	defer func() {
+		environment = previous
+	}()
+
+	return evaluate(e)
+}
+
 func isTruthy(i interface{}) bool {
 	if i == nil {
 		return false
@@ -164,13 +864,20 @@ func isTruthy(i interface{}) bool {
 }
 
 func isEqual(a, b interface{}) bool {
+	if isNumber(a) && isNumber(b) {
+		return numbersEqual(a, b)
+	}
+	if l, ok := a.(Time); ok {
+		r, ok := b.(Time)
+		return ok && l.t.Equal(r.t)
+	}
 	return a == b
 }
 
 // Runtime checks
 
 func checkNumberOperand(operator token.Token, operand interface{}) error {
-	if _, ok := operand.(float64); ok {
+	if isNumber(operand) {
 		return nil
 	}
 
@@ -178,12 +885,25 @@ func checkNumberOperand(operator token.Token, operand interface{}) error {
 }
 
 func checkNumberOperands(operator token.Token, left, right interface{}) error {
-	_, lOk := left.(float64)
-	_, rOk := right.(float64)
-
-	if lOk && rOk {
+	if isNumber(left) && isNumber(right) {
 		return nil
 	}
 
 	return NewRuntimeError(operator, "Operands must be a number.")
 }
+
+func checkIntegerOperand(operator token.Token, operand interface{}) error {
+	if isInteger(operand) {
+		return nil
+	}
+
+	return NewRuntimeError(operator, "Operand must be an integer.")
+}
+
+func checkIntegerOperands(operator token.Token, left, right interface{}) error {
+	if isInteger(left) && isInteger(right) {
+		return nil
+	}
+
+	return NewRuntimeError(operator, "Operands must be integers.")
+}
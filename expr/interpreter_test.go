@@ -0,0 +1,71 @@
+package expr_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/levi/holo/expr"
+	"github.com/levi/holo/parser"
+	"github.com/levi/holo/scanner"
+)
+
+// run interprets source as a script, returning what it printed and the error it stopped with.
+// Each script runs in a fresh module, so tests can't see each other's globals.
+func run(t *testing.T, source string) (string, error) {
+	t.Helper()
+	return execute(t, source, func(statements []expr.Stmt) error {
+		return expr.RunModule(expr.NewModule(""), statements)
+	})
+}
+
+// execute scans, parses and resolves source, then runs its statements with interpret
+func execute(t *testing.T, source string, interpret func(statements []expr.Stmt) error) (string, error) {
+	t.Helper()
+	s := scanner.NewScanner(source)
+	tokens := s.ScanTokens()
+	if len(s.Errors) > 0 {
+		t.Fatalf("%q: scanner error %q", source, s.Errors[0].Error())
+	}
+	statements, err := parser.NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("%q: parse error %q", source, err)
+	}
+	if errs := expr.Resolve(statements); len(errs) > 0 {
+		return "", errs[0]
+	}
+
+	var output bytes.Buffer
+	expr.SetOutput(&output)
+	defer expr.SetOutput(os.Stdout)
+	err = interpret(statements)
+	return output.String(), err
+}
+
+// scriptTest expects a script reading input from standard input to print output, then stop with err if it is set
+type scriptTest struct {
+	source string
+	input  string
+	output string
+	err    string
+}
+
+func runScripts(t *testing.T, tests []scriptTest) {
+	t.Helper()
+	defer expr.SetInput(os.Stdin)
+	for _, test := range tests {
+		expr.SetInput(strings.NewReader(test.input))
+		output, err := run(t, test.source)
+		if output != test.output {
+			t.Errorf("%q: printed %q, want %q", test.source, output, test.output)
+		}
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+		if message != test.err {
+			t.Errorf("%q: got error %q, want %q", test.source, message, test.err)
+		}
+	}
+}
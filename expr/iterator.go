@@ -0,0 +1,182 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+// Iterator produces the values of a for-in loop one at a time, reporting false once exhausted
+type Iterator interface {
+	Next() (interface{}, bool, error)
+}
+
+// doneValue is the type of the done builtin, which a user iterator's next() returns once exhausted
+type doneValue struct{}
+
+func (doneValue) String() string {
+	return "<done>"
+}
+
+func init() {
+	builtins.Define("done", doneValue{})
+	DefineNative(NewNative(NewSignature("iterable", []string{"iter"}, 1, false), iterableNative))
+}
+
+// Range is the half-open interval of integers produced by start..end
+type Range struct {
+	Start int64
+	End   int64
+}
+
+// NewRange allocates a range from start up to but excluding end
+func NewRange(start, end int64) *Range {
+	r := new(Range)
+	r.Start = start
+	r.End = end
+	return r
+}
+
+type listIterator struct {
+	list *List
+	next int
+}
+
+// Next re-reads the length each step, so elements appended during iteration are visited
+func (i *listIterator) Next() (interface{}, bool, error) {
+	if i.next >= len(i.list.Elements) {
+		return nil, false, nil
+	}
+	i.next++
+	return i.list.Elements[i.next-1], true, nil
+}
+
+// mapIterator yields the keys of a map, or [key, value] pairs when pairs is set
+type mapIterator struct {
+	m     *Map
+	next  int
+	pairs bool
+}
+
+func (i *mapIterator) Next() (interface{}, bool, error) {
+	if i.next >= i.m.Len() {
+		return nil, false, nil
+	}
+	entry := i.m.Entries()[i.next]
+	i.next++
+	if i.pairs {
+		return NewList([]interface{}{entry.Key, entry.Value}), true, nil
+	}
+	return entry.Key, true, nil
+}
+
+type stringIterator struct {
+	runes []rune
+	next  int
+}
+
+func (i *stringIterator) Next() (interface{}, bool, error) {
+	if i.next >= len(i.runes) {
+		return nil, false, nil
+	}
+	i.next++
+	return string(i.runes[i.next-1]), true, nil
+}
+
+type rangeIterator struct {
+	next int64
+	end  int64
+}
+
+func (i *rangeIterator) Next() (interface{}, bool, error) {
+	if i.next >= i.end {
+		return nil, false, nil
+	}
+	i.next++
+	return i.next - 1, true, nil
+}
+
+// userIterator adapts a script iterator, calling its next() until it returns done
+type userIterator struct {
+	keyword token.Token
+	next    Callable
+}
+
+func (i *userIterator) Next() (interface{}, bool, error) {
+	value, err := call(i.keyword, i.next, nil, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, ok := value.(doneValue); ok {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// Iterable is a value which for ... in iterates by first asking it for a fresh Iterator, so it can be
+// iterated more than once
+type Iterable interface {
+	Iter(keyword token.Token) (Iterator, error)
+}
+
+// userIterable opts a script into the iterator protocol. It's made by the iterable builtin from an
+// iter() function, so no data value turns into an iterator by holding a function.
+type userIterable struct {
+	iter Callable
+}
+
+func (u *userIterable) Iter(keyword token.Token) (Iterator, error) {
+	return iterateUser(keyword, u.iter)
+}
+
+func (u *userIterable) String() string {
+	return "<iterable>"
+}
+
+// iterableNative implements iterable(iter), wrapping an iter() function whose result's next() produces
+// each value in turn, then done
+func iterableNative(paren token.Token, arguments []interface{}) (interface{}, error) {
+	iter, ok := arguments[0].(Callable)
+	if !ok {
+		return nil, NewRuntimeError(paren, "Argument 'iter' of iterable() must be a function.")
+	}
+	return &userIterable{iter}, nil
+}
+
+// iterateUser calls iter() and checks that the iterator it returns has a next() function
+func iterateUser(keyword token.Token, iter Callable) (Iterator, error) {
+	value, err := call(keyword, iter, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := value.(*Map); ok {
+		if next, ok := m.Get("next"); ok {
+			if next, ok := next.(Callable); ok {
+				return &userIterator{keyword: keyword, next: next}, nil
+			}
+		}
+	}
+	return nil, NewRuntimeError(keyword, "iter() must return a map with a next() function.")
+}
+
+// iterate begins iterating over a value. Maps iterate over their keys, or [key, value] pairs when pairs is set.
+func iterate(keyword token.Token, value interface{}, pairs bool) (Iterator, error) {
+	if pairs {
+		if m, ok := value.(*Map); ok {
+			return &mapIterator{m: m, pairs: true}, nil
+		}
+		return nil, NewRuntimeError(keyword, "Only maps can be iterated as key/value pairs.")
+	}
+
+	switch v := value.(type) {
+	case *List:
+		return &listIterator{list: v}, nil
+	case *Map:
+		return &mapIterator{m: v}, nil
+	case string:
+		return &stringIterator{runes: []rune(v)}, nil
+	case *Range:
+		return &rangeIterator{next: v.Start, end: v.End}, nil
+	case Iterable:
+		return v.Iter(keyword)
+	case Iterator:
+		return v, nil
+	}
+	return nil, NewRuntimeError(keyword, "Can only iterate over lists, maps, strings and ranges.")
+}
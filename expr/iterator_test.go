@@ -0,0 +1,51 @@
+package expr_test
+
+import "testing"
+
+// counter is a script implementing the iterator protocol, counting from 1 to n
+const counter = `
+fn counter(n) {
+	fn iter() {
+		var i = 0;
+		fn next() {
+			if (i >= n) return done;
+			i += 1;
+			return i;
+		}
+		return {"next": next};
+	}
+	return iterable(iter);
+}
+`
+
+func TestForIn(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "for (x in [1, 2, 3]) print x;", output: "1\n2\n3\n"},
+		{source: `for (k in {"a": 1, "b": 2}) print k;`, output: "a\nb\n"},
+		{source: `for (k, v in {"a": 1, "b": 2}) { print k; print v; }`, output: "a\n1\nb\n2\n"},
+		{source: `for (c in "héllo") print c;`, output: "h\né\nl\nl\no\n"},
+		{source: "for (i in 0..3) print i;", output: "0\n1\n2\n"},
+		{source: "for (i in 3..0) print i;", output: ""},
+		{source: "print 0..3; print 2 in 0..3; print 3 in 0..3;", output: "0..3\ntrue\nfalse\n"},
+		{source: "var x = 0; for (x in [1, 2]) {} print x;", output: "0\n"},
+		{source: "for (x in 1) print x;", err: "Can only iterate over lists, maps, strings and ranges."},
+		{source: "for (k, v in [1]) print k;", err: "Only maps can be iterated as key/value pairs."},
+	})
+}
+
+func TestIteratorProtocol(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: counter + "for (x in counter(3)) print x;", output: "1\n2\n3\n"},
+		{source: counter + "for (x in counter(0)) print x;", output: ""},
+		{source: counter + "print sum(counter(4)), reverse(counter(2));", output: "10 [2, 1]\n"},
+		{source: counter + "var c = counter(2); for (x in c) print x; for (x in c) print x;", output: "1\n2\n1\n2\n"},
+		{source: counter + "print counter(2);", output: "<iterable>\n"},
+		{source: counter + "for (k, v in counter(2)) print k;", err: "Only maps can be iterated as key/value pairs."},
+		// A map holding an "iter" function is still plain data
+		{source: `fn iter() { return {}; } for (k in {"iter": iter}) print k;`, output: "iter\n"},
+		{source: "print done;", output: "<done>\n"},
+		{source: "iterable(1);", err: "Argument 'iter' of iterable() must be a function."},
+		{source: `fn iter() { return {}; } for (x in iterable(iter)) print x;`, err: "iter() must return a map with a next() function."},
+		{source: `fn next() { throw "stop"; } fn iter() { return {"next": next}; } for (x in iterable(iter)) print x;`, err: "Uncaught exception: stop"},
+	})
+}
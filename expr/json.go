@@ -0,0 +1,224 @@
+package expr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/levi/holo/token"
+)
+
+// The json module converts between JSON text and holo values. Objects become maps keeping
+// their key order, integers become ints or bigints and other numbers become floats.
+func init() {
+	m := DefineNativeModule("json")
+	m.exportNative("parse", []string{"text"}, 1, false, jsonParse)
+	m.exportNative("stringify", []string{"value", "indent"}, 1, false, jsonStringify)
+}
+
+func jsonParse(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	text, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		offset := decoder.InputOffset()
+		if syntaxError, ok := err.(*json.SyntaxError); ok {
+			// The offset follows the offending character
+			offset = syntaxError.Offset - 1
+		}
+		message := err.Error()
+		if err == io.EOF || err == io.ErrUnexpectedEOF || message == "unexpected end of JSON input" {
+			offset = int64(len(text))
+			message = "unexpected end of JSON input"
+		}
+		return nil, jsonError(paren, text, offset, message)
+	}
+
+	// Only whitespace may follow the value
+	rest := decoder.InputOffset()
+	for rest < int64(len(text)) && strings.ContainsRune(" \t\r\n", rune(text[rest])) {
+		rest++
+	}
+	if rest < int64(len(text)) {
+		return nil, jsonError(paren, text, rest, "unexpected data after top-level value")
+	}
+	return value, nil
+}
+
+// jsonError describes a parse error at an offset in text by its line and column
+func jsonError(paren token.Token, text string, offset int64, message string) error {
+	line, column := 1, 1
+	for _, r := range text[:offset] {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return NewRuntimeError(paren, fmt.Sprintf("Invalid JSON at line %d, column %d: %s.", line, column, message))
+}
+
+func decodeJSON(decoder *json.Decoder) (interface{}, error) {
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		if v == '[' {
+			elements := []interface{}{}
+			for decoder.More() {
+				element, err := decodeJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			_, err = decoder.Token()
+			return NewList(elements), err
+		}
+
+		m := NewMap()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(key, value)
+		}
+		_, err = decoder.Token()
+		return m, err
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		if n, ok := new(big.Int).SetString(string(v), 10); ok {
+			return n, nil
+		}
+		return v.Float64()
+	}
+	return t, nil
+}
+
+// maxJSONIndent bounds the indent of json.stringify(), as a number of spaces or the length of an indent string
+const maxJSONIndent = 10
+
+func jsonStringify(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	indent := ""
+	switch i := arguments[1].(type) {
+	case nil, omitted:
+	case int64:
+		if i < 0 || i > maxJSONIndent {
+			return nil, indentError(paren, signature)
+		}
+		indent = strings.Repeat(" ", int(i))
+	case string:
+		if len(i) > maxJSONIndent {
+			return nil, indentError(paren, signature)
+		}
+		indent = i
+	default:
+		return nil, indentError(paren, signature)
+	}
+
+	var compact bytes.Buffer
+	err := encodeJSON(paren, &compact, arguments[0], make(map[interface{}]bool))
+	if err != nil {
+		return nil, err
+	}
+	if indent == "" {
+		return compact.String(), nil
+	}
+
+	var indented bytes.Buffer
+	json.Indent(&indented, compact.Bytes(), "", indent)
+	return indented.String(), nil
+}
+
+func indentError(paren token.Token, signature *Signature) error {
+	return argumentError(paren, signature, 1, fmt.Sprintf("an integer from 0 to %d or a string of at most %d characters", maxJSONIndent, maxJSONIndent))
+}
+
+// encodeJSON writes a value as compact JSON, failing on values JSON can't represent
+func encodeJSON(paren token.Token, b *bytes.Buffer, value interface{}, seen map[interface{}]bool) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool, string:
+		encoder := json.NewEncoder(b)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(v)
+		// Encode ends each value with a newline
+		b.Truncate(b.Len() - 1)
+	case int64, *big.Int:
+		b.WriteString(fmt.Sprint(v))
+	case *Decimal:
+		b.WriteString(formatDecimal(v))
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return NewRuntimeError(paren, "Cannot convert "+formatFloat(v)+" to JSON.")
+		}
+		// Keeping the fraction lets floats parse back as floats
+		b.WriteString(formatFloat(v))
+	case *List:
+		if seen[v] {
+			return NewRuntimeError(paren, "Cannot convert a list containing itself to JSON.")
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		b.WriteByte('[')
+		for i, element := range v.Elements {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			err := encodeJSON(paren, b, element, seen)
+			if err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case *Map:
+		if seen[v] {
+			return NewRuntimeError(paren, "Cannot convert a map containing itself to JSON.")
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		b.WriteByte('{')
+		for i, entry := range v.Entries() {
+			key, ok := entry.Key.(string)
+			if !ok {
+				return NewRuntimeError(paren, "JSON object keys must be strings, got "+format(entry.Key, true, seen)+".")
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			encodeJSON(paren, b, key, seen)
+			b.WriteByte(':')
+			err := encodeJSON(paren, b, entry.Value, seen)
+			if err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return NewRuntimeError(paren, "Cannot convert "+stringify(v)+" to JSON.")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package expr_test
+
+import "testing"
+
+func TestJSON(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{
+			source: "var v = json.parse(io.readAll()); print v; print v[\"a\"][1] + 1;",
+			input:  `{"a": [1, 2.5, true, null, "x"], "b": {}}`,
+			output: "{\"a\": [1, 2.5, true, nil, \"x\"], \"b\": {}}\n3.5\n",
+		},
+		// Objects keep their key order, large integers become bigints and other numbers floats
+		{source: "print json.parse(io.readAll());", input: `{"z": 1, "a": 2}`, output: "{\"z\": 1, \"a\": 2}\n"},
+		{source: "print json.parse(\"123456789012345678901234567890\") + 1n;", output: "123456789012345678901234567891\n"},
+		{source: "print json.parse(\"1e2\"); print json.parse(\"1.0\"); print json.parse(\" [] \");", output: "100.0\n1.0\n[]\n"},
+		{source: "print json.stringify(json.parse(io.readAll()));", input: `{"a":[1,2.5,true,null,"x<>"],"b":{}}`, output: "{\"a\":[1,2.5,true,null,\"x<>\"],\"b\":{}}\n"},
+		{source: "print json.stringify([1.0, 1d / 4d, 2n ** 70n]);", output: "[1.0,0.25,1180591620717411303424]\n"},
+		{source: "print json.stringify([1, [2]], 2);", output: "[\n  1,\n  [\n    2\n  ]\n]\n"},
+		{source: "print json.stringify({\"k\": nil}, \"\t\");", output: "{\n\t\"k\": null\n}\n"},
+		{source: "print json.stringify({1: nil});", err: "JSON object keys must be strings, got 1."},
+		{source: "print json.stringify([1], 0); print json.stringify([1], nil);", output: "[1]\n[1]\n"},
+		{source: "print json.stringify([1], -1);", err: "Argument 'indent' of json.stringify() must be an integer from 0 to 10 or a string of at most 10 characters."},
+		{source: "print json.stringify([1], 11);", err: "Argument 'indent' of json.stringify() must be an integer from 0 to 10 or a string of at most 10 characters."},
+		{source: "print json.stringify([1], -9223372036854775807);", err: "Argument 'indent' of json.stringify() must be an integer from 0 to 10 or a string of at most 10 characters."},
+		{source: "print json.stringify([1], \"           \");", err: "Argument 'indent' of json.stringify() must be an integer from 0 to 10 or a string of at most 10 characters."},
+		{source: "print json.stringify([1], true);", err: "Argument 'indent' of json.stringify() must be an integer from 0 to 10 or a string of at most 10 characters."},
+		{source: "print json.stringify(math.nan);", err: "Cannot convert NaN to JSON."},
+		{source: "var xs = [1]; xs[0] = xs; print json.stringify(xs);", err: "Cannot convert a list containing itself to JSON."},
+		{source: "print json.parse(io.readAll());", input: "[1,\n  2,", err: "Invalid JSON at line 2, column 5: unexpected end of JSON input."},
+		{source: "print json.parse(io.readAll());", input: "{\n  \"a\" 1}", err: "Invalid JSON at line 2, column 7: invalid character '1' after object key."},
+		{source: "print json.parse(\"[1] [2]\");", err: "Invalid JSON at line 1, column 5: unexpected data after top-level value."},
+		{source: "try { json.parse(\"\"); } catch (e) { print e.message; }", output: "Invalid JSON at line 1, column 1: unexpected end of JSON input.\n"},
+	})
+}
@@ -0,0 +1,52 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+// List is a mutable sequence of values, shared by reference. Frozen lists can't be modified.
+type List struct {
+	Elements []interface{}
+	Frozen   bool
+}
+
+// NewList allocates a list holding elements
+func NewList(elements []interface{}) *List {
+	l := new(List)
+	l.Elements = elements
+	return l
+}
+
+// listIndex resolves a possibly negative index into an offset within a sequence of length n
+func listIndex(bracket token.Token, index interface{}, n int) (int, error) {
+	i, ok := index.(int64)
+	if !ok {
+		return 0, NewRuntimeError(bracket, "List index must be an integer.")
+	}
+	if i < 0 {
+		i += int64(n)
+	}
+	if i < 0 || i >= int64(n) {
+		return 0, NewRuntimeError(bracket, "List index out of range.")
+	}
+	return int(i), nil
+}
+
+// sliceBound resolves a possibly negative or nil slice bound, clamping it to a sequence of length n
+func sliceBound(bracket token.Token, bound interface{}, n int, fallback int) (int, error) {
+	if bound == nil {
+		return fallback, nil
+	}
+	i, ok := bound.(int64)
+	if !ok {
+		return 0, NewRuntimeError(bracket, "Slice bounds must be integers.")
+	}
+	if i < 0 {
+		i += int64(n)
+	}
+	if i < 0 {
+		return 0, nil
+	}
+	if i > int64(n) {
+		return n, nil
+	}
+	return int(i), nil
+}
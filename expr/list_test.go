@@ -0,0 +1,23 @@
+package expr_test
+
+import "testing"
+
+func TestLists(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print [1, 2, 3];", output: "[1, 2, 3]\n"},
+		{source: `print [1, "a", nil, [true]];`, output: "[1, \"a\", nil, [true]]\n"},
+		{source: "var xs = [1, 2, 3]; print xs[0]; print xs[-1]; print xs[-3];", output: "1\n3\n1\n"},
+		{source: "var xs = [1, 2, 3]; xs[-1] = 9; print xs;", output: "[1, 2, 9]\n"},
+		{source: "var xs = [1, 2, 3, 4]; print xs[1:3]; print xs[:2]; print xs[2:]; print xs[-2:];", output: "[2, 3]\n[1, 2]\n[3, 4]\n[3, 4]\n"},
+		{source: "var xs = [1, 2]; print xs[5:]; print xs[-9:1]; print xs[1:0];", output: "[]\n[1]\n[]\n"},
+		{source: "var xs = [1]; var ys = xs[:]; ys[0] = 2; print xs;", output: "[1]\n"},
+		{source: "var xs = [1]; var ys = xs; ys[0] = 2; print xs;", output: "[2]\n"},
+		{source: "var xs = [1]; xs[0] = xs; print xs;", output: "[[...]]\n"},
+		{source: "print [1, 2] == [1, 2];", output: "false\n"},
+		{source: "print [1, 2][2];", err: "List index out of range."},
+		{source: "print [1, 2][-3];", err: "List index out of range."},
+		{source: "var xs = []; xs[0] = 1;", err: "List index out of range."},
+		{source: `print [1]["a"];`, err: "List index must be an integer."},
+		{source: "print 1[0];", err: "Only lists and maps can be indexed."},
+	})
+}
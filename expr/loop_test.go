@@ -0,0 +1,24 @@
+package expr_test
+
+import "testing"
+
+func TestLoopControl(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "var i = 0; while (true) { i += 1; if (i == 3) break; } print i;", output: "3\n"},
+		{source: "for (i in 0..5) { if (i % 2 == 0) continue; print i; }", output: "1\n3\n"},
+		{source: "var i = 0; while (i < 4) { i += 1; if (i == 2) continue; print i; }", output: "1\n3\n4\n"},
+		{
+			source: "outer: for (i in 0..3) { for (j in 0..3) { if (j == 1) continue outer; if (i == 2) break outer; print i; print j; } } print \"done\";",
+			output: "0\n0\n1\n0\ndone\n",
+		},
+		{
+			source: "var n = 0; outer: while (true) { while (true) { n += 1; break outer; } } print n;",
+			output: "1\n",
+		},
+		// Unwinding restores the enclosing environment
+		{source: "var x = 1; for (i in 0..2) { var x = 2; break; } print x;", output: "1\n"},
+		{source: "fn f() { for (i in 0..3) { if (i == 1) return i; } } print f();", output: "1\n"},
+		{source: "for (i in 0..2) { try { break; } finally { print \"finally\"; } }", output: "finally\n"},
+		{source: "for (i in 0..2) { try { throw 1; } catch (e) { continue; } print i; } print \"after\";", output: "after\n"},
+	})
+}
@@ -0,0 +1,116 @@
+package expr
+
+import (
+	"math"
+	"math/big"
+)
+
+// Map is a mutable mapping from keys to values, shared by reference and iterated in insertion order.
+// Keys must be strings, numbers, bools or nil. Frozen maps can't be modified by scripts.
+type Map struct {
+	Frozen bool
+
+	entries []MapEntry
+	index   map[interface{}]int
+}
+
+// MapEntry is a single key/value pair of a map
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// bigKey hashes numbers which have no exact int64 or float64 representation
+type bigKey struct {
+	value string
+}
+
+// NewMap allocates an empty map
+func NewMap() *Map {
+	m := new(Map)
+	m.index = make(map[interface{}]int)
+	return m
+}
+
+// Get looks up the value stored for key
+func (m *Map) Get(key interface{}) (interface{}, bool) {
+	hash, ok := hashKey(key)
+	if !ok {
+		return nil, false
+	}
+	if i, ok := m.index[hash]; ok {
+		return m.entries[i].Value, true
+	}
+	return nil, false
+}
+
+// Set stores value for key, keeping the original position of an existing key.
+// It reports false when key is not hashable.
+func (m *Map) Set(key, value interface{}) bool {
+	hash, ok := hashKey(key)
+	if !ok {
+		return false
+	}
+	if i, ok := m.index[hash]; ok {
+		m.entries[i].Value = value
+		return true
+	}
+	m.index[hash] = len(m.entries)
+	m.entries = append(m.entries, MapEntry{key, value})
+	return true
+}
+
+// Has determines if the map contains key
+func (m *Map) Has(key interface{}) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Len is the number of entries in the map
+func (m *Map) Len() int {
+	return len(m.entries)
+}
+
+// Entries lists the key/value pairs of the map in insertion order
+func (m *Map) Entries() []MapEntry {
+	return m.entries
+}
+
+func isHashable(key interface{}) bool {
+	_, ok := hashKey(key)
+	return ok
+}
+
+// hashKey normalizes a key so that keys equal under isEqual share a hash. Integral numbers hash
+// as int64, other numbers exactly representable as a float64 hash as that float. NaN is never equal
+// to itself, so it can't be found again and isn't hashable.
+func hashKey(key interface{}) (interface{}, bool) {
+	switch k := key.(type) {
+	case nil, bool, string, int64:
+		return k, true
+	case float64:
+		if math.IsNaN(k) {
+			return nil, false
+		}
+		if k == math.Trunc(k) && k >= math.MinInt64 && k < math.MaxInt64 {
+			return int64(k), true
+		}
+		return k, true
+	case *big.Int:
+		if k.IsInt64() {
+			return k.Int64(), true
+		}
+		return hashKey(NewDecimal(new(big.Rat).SetInt(k), 0))
+	case *Decimal:
+		// Decimals equal whatever their scale, so 1.5d and 1.50d are the same key
+		r := k.Rat
+		if r.IsInt() && r.Num().IsInt64() {
+			return r.Num().Int64(), true
+		}
+		if f, exact := r.Float64(); exact {
+			return hashKey(f)
+		}
+		return bigKey{r.RatString()}, true
+	}
+	return nil, false
+}
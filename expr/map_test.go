@@ -0,0 +1,23 @@
+package expr_test
+
+import "testing"
+
+func TestMaps(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print {"a": 1, "b": [2]};`, output: "{\"a\": 1, \"b\": [2]}\n"},
+		{source: `var m = {"a": 1}; m["b"] = 2; m["a"] = 3; print m;`, output: "{\"a\": 3, \"b\": 2}\n"},
+		{source: `var m = {"b": 1, "a": 2, "c": 3}; m["d"] = 4; print m;`, output: "{\"b\": 1, \"a\": 2, \"c\": 3, \"d\": 4}\n"},
+		{source: `var m = {"a": 1}; print "a" in m; print "b" in m;`, output: "true\nfalse\n"},
+		{source: "var m = {1: \"int\"}; print m[1.0]; print m[1n]; print m[1d];", output: "int\nint\nint\n"},
+		{source: "var m = {true: 1, nil: 2}; print m[true]; print m[nil];", output: "1\n2\n"},
+		{source: "var m = {0.5: 1}; print m[1d / 2d];", output: "1\n"},
+		{source: "var m = {1: 1, 1.0: 2}; print m;", output: "{1: 2}\n"},
+		{source: "var m = {}; m[m] = 1;", err: "Map keys must be strings, numbers, bools or nil."},
+		{source: "print {[1]: 2};", err: "Map keys must be strings, numbers, bools or nil."},
+		// NaN is never equal to itself, so it can't be a key
+		{source: "var m = {}; m[math.nan] = 1;", err: "Map keys must be strings, numbers, bools or nil."},
+		{source: "print {math.nan: 1};", err: "Map keys must be strings, numbers, bools or nil."},
+		{source: "print math.nan in {1: 2};", output: "false\n"},
+		{source: `print {"a": 1}["b"];`, err: "Undefined key \"b\"."},
+	})
+}
@@ -0,0 +1,22 @@
+package expr_test
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print match (2) { 1 => "one", 2 => "two", _ => "other" };`, output: "two\n"},
+		{source: `print match ("y") { "x" | "y" => "xy", _ => "other" };`, output: "xy\n"},
+		{source: `print match (9) { 1 => "one", n => n * 2 };`, output: "18\n"},
+		{source: "print match (-1) { -1 => \"minus one\", _ => \"other\" };", output: "minus one\n"},
+		{source: "print match (1.0) { 1 => \"int\", _ => \"other\" };", output: "int\n"},
+		{source: "print match ([1, [2]]) { [1, [x]] => x };", output: "2\n"},
+		{source: `print match ({"name": "ann", "age": 3}) { {name: n} => n };`, output: "ann\n"},
+		{source: `print match ({"a": 1}) { {b} => b, {a} => a };`, output: "1\n"},
+		{source: "print match ([1, 2, 3]) { [a] => a, [a, ...rest] => rest };", output: "[2, 3]\n"},
+		{source: "print match (5) { n if n > 9 => \"big\", n if n > 3 => \"medium\", _ => \"small\" };", output: "medium\n"},
+		{source: "print match (nil) { true => 1, false => 2, nil => 3 };", output: "3\n"},
+		{source: "var n = 1; print match (2) { n => n }; print n;", output: "2\n1\n"},
+		{source: "print match (3) { 1 => 1, 2 => 2 };", err: "No match for value 3."},
+		{source: `print match ("a") { [x] => x };`, err: "No match for value \"a\"."},
+	})
+}
@@ -0,0 +1,83 @@
+package expr
+
+// Matcher tests a value against a pattern, collecting the variables the pattern binds
+type Matcher interface {
+	Matches(value interface{}, bindings map[string]interface{}) (bool, error)
+}
+
+func (a AlternativePattern) Matches(value interface{}, bindings map[string]interface{}) (bool, error) {
+	for _, alternative := range a.alternatives {
+		// Bindings of an alternative are only kept when it matches
+		attempt := make(map[string]interface{})
+		ok, err := matches(alternative, value, attempt)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			for name, bound := range attempt {
+				bindings[name] = bound
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b BindingPattern) Matches(value interface{}, bindings map[string]interface{}) (bool, error) {
+	bindings[b.name.Lexeme] = value
+	return true, nil
+}
+
+func (l ListPattern) Matches(value interface{}, bindings map[string]interface{}) (bool, error) {
+	list, ok := value.(*List)
+	if !ok || len(list.Elements) < len(l.elements) {
+		return false, nil
+	}
+	if l.rest == nil && len(list.Elements) != len(l.elements) {
+		return false, nil
+	}
+	for i, element := range l.elements {
+		ok, err := matches(element, list.Elements[i], bindings)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if l.rest != nil {
+		return matches(l.rest, restOf(list, len(l.elements)), bindings)
+	}
+	return true, nil
+}
+
+func (l LiteralPattern) Matches(value interface{}, bindings map[string]interface{}) (bool, error) {
+	literal, err := evaluate(l.value)
+	if err != nil {
+		return false, err
+	}
+	return isEqual(value, literal), nil
+}
+
+func (m MapPattern) Matches(value interface{}, bindings map[string]interface{}) (bool, error) {
+	object, ok := value.(*Map)
+	if !ok {
+		return false, nil
+	}
+	for i, key := range m.keys {
+		entry, ok := object.Get(key)
+		if !ok {
+			return false, nil
+		}
+		ok, err := matches(m.values[i], entry, bindings)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (w WildcardPattern) Matches(value interface{}, bindings map[string]interface{}) (bool, error) {
+	return true, nil
+}
+
+func matches(pattern Pattern, value interface{}, bindings map[string]interface{}) (bool, error) {
+	return pattern.(Matcher).Matches(value, bindings)
+}
@@ -0,0 +1,156 @@
+package expr
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/levi/holo/token"
+)
+
+// The math module. Rounding, abs, min and max keep the kind of their arguments, so integers and
+// decimals stay exact; the other functions convert their arguments to floats.
+func init() {
+	m := DefineNativeModule("math")
+	m.Export("pi", math.Pi)
+	m.Export("e", math.E)
+	m.Export("inf", math.Inf(1))
+	m.Export("nan", math.NaN())
+
+	m.exportNative("floor", []string{"x"}, 1, false, rounding(math.Floor, floorDecimal))
+	m.exportNative("ceil", []string{"x"}, 1, false, rounding(math.Ceil, ceilDecimal))
+	m.exportNative("round", []string{"x"}, 1, false, rounding(math.Round, roundDecimal))
+	m.exportNative("abs", []string{"x"}, 1, false, mathAbs)
+	m.exportNative("min", []string{"x", "rest"}, 1, true, extreme(-1))
+	m.exportNative("max", []string{"x", "rest"}, 1, true, extreme(1))
+	m.exportNative("isNaN", []string{"x"}, 1, false, mathIsNaN)
+	m.exportNative("pow", []string{"x", "y"}, 2, false, floatFunction2(math.Pow))
+	m.exportNative("atan2", []string{"y", "x"}, 2, false, floatFunction2(math.Atan2))
+
+	functions := map[string]func(float64) float64{
+		"sqrt":  math.Sqrt,
+		"sin":   math.Sin,
+		"cos":   math.Cos,
+		"tan":   math.Tan,
+		"asin":  math.Asin,
+		"acos":  math.Acos,
+		"atan":  math.Atan,
+		"exp":   math.Exp,
+		"log":   math.Log,
+		"log2":  math.Log2,
+		"log10": math.Log10,
+	}
+	for name, f := range functions {
+		m.exportNative(name, []string{"x"}, 1, false, floatFunction(f))
+	}
+}
+
+// floatFunction adapts a float function of one argument to a native
+func floatFunction(f func(float64) float64) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		x, err := numberArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		return f(toFloat(x)), nil
+	}
+}
+
+// floatFunction2 adapts a float function of two arguments to a native
+func floatFunction2(f func(float64, float64) float64) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		x, err := numberArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		y, err := numberArgument(paren, signature, arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(toFloat(x), toFloat(y)), nil
+	}
+}
+
+// rounding adapts a float rounding function and its exact decimal counterpart to a native. Integers are returned unchanged.
+func rounding(f func(float64) float64, d func(*big.Rat) *big.Int) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		x, err := numberArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		switch n := x.(type) {
+		case *Decimal:
+			return NewDecimal(new(big.Rat).SetInt(d(n.Rat)), 0), nil
+		case float64:
+			return f(n), nil
+		}
+		return x, nil
+	}
+}
+
+func floorDecimal(r *big.Rat) *big.Int {
+	// Euclidean division rounds towards negative infinity for a positive divisor
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+func ceilDecimal(r *big.Rat) *big.Int {
+	return new(big.Int).Neg(floorDecimal(new(big.Rat).Neg(r)))
+}
+
+// roundDecimal rounds half away from zero, like math.Round
+func roundDecimal(r *big.Rat) *big.Int {
+	half := big.NewRat(1, 2)
+	if r.Sign() < 0 {
+		return new(big.Int).Neg(floorDecimal(new(big.Rat).Add(new(big.Rat).Neg(r), half)))
+	}
+	return floorDecimal(new(big.Rat).Add(r, half))
+}
+
+func mathAbs(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	x, err := numberArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch n := x.(type) {
+	case int64:
+		if n == math.MinInt64 {
+			return nil, NewRuntimeError(paren, "Integer overflow.")
+		}
+		if n < 0 {
+			return -n, nil
+		}
+		return n, nil
+	case *big.Int:
+		return new(big.Int).Abs(n), nil
+	case *Decimal:
+		return NewDecimal(new(big.Rat).Abs(n.Rat), n.Scale), nil
+	}
+	return math.Abs(x.(float64)), nil
+}
+
+// extreme finds the smallest (sign -1) or largest (sign 1) of its arguments, comparing numbers exactly unless either is a float
+func extreme(sign int) func(token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		result, err := numberArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range arguments[1].(*List).Elements {
+			if !isNumber(value) {
+				return nil, argumentError(paren, signature, 1, "numbers")
+			}
+			if compareNumbers(value, result)*sign > 0 {
+				result = value
+			}
+		}
+		return result, nil
+	}
+}
+
+func mathIsNaN(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	x, err := numberArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := x.(float64)
+	return ok && math.IsNaN(f), nil
+}
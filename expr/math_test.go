@@ -0,0 +1,24 @@
+package expr_test
+
+import "testing"
+
+func TestMathModule(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print math.floor(2.5); print math.ceil(2.1); print math.round(-2.5); print math.round(2.4);", output: "2.0\n3.0\n-3.0\n2.0\n"},
+		// Integers stay integers and decimals round exactly
+		{source: "print math.floor(3); print math.round(7n); print math.floor(7d / 2d); print math.ceil(-7d / 2d);", output: "3\n7\n3\n-3\n"},
+		{source: "print math.round(5d / 2d); print math.round(-5d / 2d); print math.round(1d / 3d);", output: "3\n-3\n0\n"},
+		{source: "print math.abs(-3); print math.abs(-2.5); print math.abs(-5n); print math.abs(-1d / 2d);", output: "3\n2.5\n5\n0.5\n"},
+		{source: "print math.min(3, 1.5, 2); print math.max(1, 2n, 3d); print math.min(1); print math.max(2, 2.0);", output: "1.5\n3\n1\n2\n"},
+		{source: "print math.sqrt(16); print math.pow(2, 10); print math.exp(0); print math.log(1); print math.log2(8); print math.log10(100);", output: "4.0\n1024.0\n1.0\n0.0\n3.0\n2.0\n"},
+		{source: "print math.sin(0); print math.cos(0); print math.atan2(0, 1); print math.floor(math.tan(math.pi / 4) + 0.5);", output: "0.0\n1.0\n0.0\n1.0\n"},
+		{source: "print math.pi; print math.e;", output: "3.141592653589793\n2.718281828459045\n"},
+		{source: "print math.inf; print -math.inf; print math.nan; print math.sqrt(-1);", output: "+Inf\n-Inf\nNaN\nNaN\n"},
+		{source: "print math.isNaN(math.nan); print math.isNaN(1); print math.isNaN(1d); print math.nan == math.nan;", output: "true\nfalse\nfalse\nfalse\n"},
+		{source: "print math.abs(-9223372036854775807 - 1);", err: "Integer overflow."},
+		{source: `print math.sqrt("4");`, err: "Argument 'x' of math.sqrt() must be a number."},
+		{source: "print math.max(1, nil);", err: "Argument 'rest' of math.max() must be numbers."},
+		{source: "print math.pow(2);", err: "Expected 2 arguments but got 1."},
+		{source: "print math.tau;", err: "Module 'math' does not export 'tau'."},
+	})
+}
@@ -0,0 +1,108 @@
+package expr
+
+import (
+	"math/rand"
+
+	"github.com/levi/holo/token"
+)
+
+// Module is a script with its own global environment and source of random values. Importers read its
+// exported names with '.'
+type Module struct {
+	Path string
+
+	globals *Environment
+	exports map[string]bool
+	random  *rand.Rand
+}
+
+// NewModule allocates a module for the script at path, with a global environment enclosing the builtins
+func NewModule(path string) *Module {
+	m := new(Module)
+	m.Path = path
+	m.globals = NewEnvironment(builtins)
+	m.exports = make(map[string]bool)
+	m.random = newRandomSource()
+	return m
+}
+
+// ModuleLoader locates, runs and caches the modules imported by scripts
+type ModuleLoader interface {
+	Load(keyword token.Token, importer *Module, path string) (*Module, error)
+}
+
+var loader ModuleLoader
+
+// mainModule is the script run by Interpret, currentModule is the module whose code is executing
+var mainModule = NewModule("")
+var currentModule = mainModule
+
+// SetModuleLoader configures how import statements load modules
+func SetModuleLoader(l ModuleLoader) {
+	loader = l
+}
+
+// MainModule is the module Interpret runs statements in, with an empty path for the REPL
+func MainModule() *Module {
+	return mainModule
+}
+
+// RunModule executes the statements of a module within its own global environment
+func RunModule(m *Module, statements []Stmt) error {
+	previous := currentModule
+	currentModule = m
+	defer func() {
+		currentModule = previous
+	}()
+
+	return executeBlock(statements, m.globals)
+}
+
+// DefineNativeModule registers an empty module of Go builtins, visible from every module as name
+func DefineNativeModule(name string) *Module {
+	m := NewModule(name)
+	builtins.Define(name, m)
+	return m
+}
+
+// Export defines an exported name in a module
+func (m *Module) Export(name string, value interface{}) {
+	m.globals.Define(name, value)
+	m.exports[name] = true
+}
+
+// exportNative exports a builtin function from a native module. It is named after the module in errors
+// and stack traces, and given its signature to report bad arguments.
+func (m *Module) exportNative(name string, params []string, required int, variadic bool, function func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error)) {
+	signature := NewSignature(m.Path+"."+name, params, required, variadic)
+	m.Export(name, NewNative(signature, func(paren token.Token, arguments []interface{}) (interface{}, error) {
+		return function(paren, signature, arguments)
+	}))
+}
+
+func (m *Module) String() string {
+	return "<module " + m.Path + ">"
+}
+
+// property looks up an exported name of a module
+func (m *Module) property(name token.Token) (interface{}, error) {
+	if !m.exports[name.Lexeme] {
+		return nil, NewRuntimeError(name, "Module '"+m.Path+"' does not export '"+name.Lexeme+"'.")
+	}
+	return m.globals.Get(name)
+}
+
+// declaredNames lists the variables a declaration defines
+func declaredNames(declaration Stmt) []string {
+	switch d := declaration.(type) {
+	case Var:
+		return []string{d.name.Lexeme}
+	case Const:
+		return []string{d.name.Lexeme}
+	case Fn:
+		return []string{d.name.Lexeme}
+	case VarPattern:
+		return patternNames(d.pattern)
+	}
+	return nil
+}
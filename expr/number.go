@@ -0,0 +1,594 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/levi/holo/token"
+)
+
+// Numbers are int64 integers, *big.Int bigints, *Decimal decimals or float64 floats.
+// Mixed operations promote both operands to the wider kind, in the order below. Floats
+// are the widest kind as any float operand makes the result inexact.
+const (
+	intKind = iota
+	bigIntKind
+	decimalKind
+	floatKind
+	notNumber
+)
+
+// decimalPrecision is the number of fractional digits shown for decimals without a
+// terminating decimal expansion, such as 1d / 3
+const decimalPrecision = 20
+
+// maxBigIntBits bounds the size of bigint and decimal results of ** and << so that a
+// script can't exhaust memory computing them
+const maxBigIntBits = 1 << 20
+
+// Decimal is an exact decimal number. Scale is the least number of fractional digits it prints
+// with, so that amounts such as 1.50d keep their trailing zeros.
+type Decimal struct {
+	Rat   *big.Rat
+	Scale int
+}
+
+// NewDecimal allocates a decimal with the value of r, printed with at least scale fractional digits
+func NewDecimal(r *big.Rat, scale int) *Decimal {
+	d := new(Decimal)
+	d.Rat = r
+	d.Scale = scale
+	return d
+}
+
+// decimalScale is the scale of a decimal, and zero for any other number
+func decimalScale(value interface{}) int {
+	if d, ok := value.(*Decimal); ok {
+		return d.Scale
+	}
+	return 0
+}
+
+func numberKind(value interface{}) int {
+	switch value.(type) {
+	case int64:
+		return intKind
+	case *big.Int:
+		return bigIntKind
+	case *Decimal:
+		return decimalKind
+	case float64:
+		return floatKind
+	}
+	return notNumber
+}
+
+func isNumber(value interface{}) bool {
+	return numberKind(value) != notNumber
+}
+
+func widerKind(left, right interface{}) int {
+	l, r := numberKind(left), numberKind(right)
+	if l > r {
+		return l
+	}
+	return r
+}
+
+func toFloat(value interface{}) float64 {
+	switch n := value.(type) {
+	case int64:
+		return float64(n)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f
+	case *Decimal:
+		f, _ := n.Rat.Float64()
+		return f
+	case float64:
+		return n
+	}
+	return math.NaN()
+}
+
+func toBigInt(value interface{}) *big.Int {
+	switch n := value.(type) {
+	case int64:
+		return big.NewInt(n)
+	case *big.Int:
+		return n
+	}
+	return nil
+}
+
+func toDecimal(value interface{}) *big.Rat {
+	switch n := value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(n)
+	case *big.Int:
+		return new(big.Rat).SetInt(n)
+	case *Decimal:
+		return n.Rat
+	}
+	return nil
+}
+
+// arithmetic applies a numeric binary operator, expecting both operands to be numbers
+func arithmetic(operator token.Token, left, right interface{}) (interface{}, error) {
+	switch widerKind(left, right) {
+	case intKind:
+		return intArithmetic(operator, left.(int64), right.(int64))
+	case bigIntKind:
+		return bigIntArithmetic(operator, toBigInt(left), toBigInt(right))
+	case decimalKind:
+		// Results keep the larger scale of the operands, so 19.99d + 0.01d is 20.00
+		scale := decimalScale(left)
+		if decimalScale(right) > scale {
+			scale = decimalScale(right)
+		}
+		return decimalArithmetic(operator, toDecimal(left), toDecimal(right), scale)
+	}
+	return floatArithmetic(operator, toFloat(left), toFloat(right))
+}
+
+func intArithmetic(operator token.Token, l, r int64) (interface{}, error) {
+	switch operator.TokenType {
+	case token.PlusToken:
+		sum := l + r
+		if (l^sum)&(r^sum) < 0 {
+			return nil, NewRuntimeError(operator, "Integer overflow.")
+		}
+		return sum, nil
+	case token.MinusToken:
+		diff := l - r
+		if (l^r)&(l^diff) < 0 {
+			return nil, NewRuntimeError(operator, "Integer overflow.")
+		}
+		return diff, nil
+	case token.StarToken:
+		product, ok := multiplyInt(l, r)
+		if !ok {
+			return nil, NewRuntimeError(operator, "Integer overflow.")
+		}
+		return product, nil
+	case token.SlashToken:
+		if r == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		if l%r != 0 {
+			return float64(l) / float64(r), nil
+		}
+		if l == math.MinInt64 && r == -1 {
+			return nil, NewRuntimeError(operator, "Integer overflow.")
+		}
+		return l / r, nil
+	case token.SlashSlashToken:
+		if r == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		if l == math.MinInt64 && r == -1 {
+			return nil, NewRuntimeError(operator, "Integer overflow.")
+		}
+		quotient := l / r
+		if l%r != 0 && (l < 0) != (r < 0) {
+			quotient--
+		}
+		return quotient, nil
+	case token.PercentToken:
+		if r == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		modulo := l % r
+		if modulo != 0 && (modulo < 0) != (r < 0) {
+			modulo += r
+		}
+		return modulo, nil
+	case token.StarStarToken:
+		if r < 0 {
+			return math.Pow(float64(l), float64(r)), nil
+		}
+		// Exponentiation by squaring, checking each multiplication for overflow
+		result, base := int64(1), l
+		for r > 0 {
+			var ok bool
+			if r&1 == 1 {
+				if result, ok = multiplyInt(result, base); !ok {
+					return nil, NewRuntimeError(operator, "Integer overflow.")
+				}
+			}
+			r >>= 1
+			if r > 0 {
+				if base, ok = multiplyInt(base, base); !ok {
+					return nil, NewRuntimeError(operator, "Integer overflow.")
+				}
+			}
+		}
+		return result, nil
+	}
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+// multiplyInt multiplies two integers, reporting false on overflow
+func multiplyInt(l, r int64) (int64, bool) {
+	if l == 0 || r == 0 {
+		return 0, true
+	}
+	product := l * r
+	if product/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
+
+// checkExpBits estimates the bits in base ** exponent as the exponent times the bits in the base
+func checkExpBits(operator token.Token, base, exponent *big.Int) error {
+	if base.CmpAbs(big.NewInt(1)) <= 0 {
+		// 0, 1 and -1 stay small whatever the exponent
+		return nil
+	}
+	if !exponent.IsInt64() || exponent.Int64() > maxBigIntBits/int64(base.BitLen()) {
+		return NewRuntimeError(operator, "Result too large.")
+	}
+	return nil
+}
+
+func bigIntArithmetic(operator token.Token, l, r *big.Int) (interface{}, error) {
+	switch operator.TokenType {
+	case token.PlusToken:
+		return new(big.Int).Add(l, r), nil
+	case token.MinusToken:
+		return new(big.Int).Sub(l, r), nil
+	case token.StarToken:
+		return new(big.Int).Mul(l, r), nil
+	case token.SlashToken:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		quotient, remainder := new(big.Int).QuoRem(l, r, new(big.Int))
+		if remainder.Sign() != 0 {
+			// Inexact bigint division stays exact as a decimal
+			return NewDecimal(new(big.Rat).SetFrac(l, r), 0), nil
+		}
+		return quotient, nil
+	case token.SlashSlashToken, token.PercentToken:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		quotient, modulo := new(big.Int).QuoRem(l, r, new(big.Int))
+		if modulo.Sign() != 0 && modulo.Sign() != r.Sign() {
+			quotient.Sub(quotient, big.NewInt(1))
+			modulo.Add(modulo, r)
+		}
+		if operator.TokenType == token.PercentToken {
+			return modulo, nil
+		}
+		return quotient, nil
+	case token.StarStarToken:
+		if !r.IsInt64() {
+			return nil, NewRuntimeError(operator, "Exponent too large.")
+		}
+		if r.Sign() < 0 {
+			return decimalArithmetic(operator, new(big.Rat).SetInt(l), new(big.Rat).SetInt(r), 0)
+		}
+		err := checkExpBits(operator, l, r)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Exp(l, r, nil), nil
+	}
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+func decimalArithmetic(operator token.Token, l, r *big.Rat, scale int) (interface{}, error) {
+	switch operator.TokenType {
+	case token.PlusToken:
+		return NewDecimal(new(big.Rat).Add(l, r), scale), nil
+	case token.MinusToken:
+		return NewDecimal(new(big.Rat).Sub(l, r), scale), nil
+	case token.StarToken:
+		return NewDecimal(new(big.Rat).Mul(l, r), scale), nil
+	case token.SlashToken:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		return NewDecimal(new(big.Rat).Quo(l, r), scale), nil
+	case token.SlashSlashToken, token.PercentToken:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero.")
+		}
+		quotient := new(big.Rat).Quo(l, r)
+		// Denominators are always positive, so Euclidean division floors
+		floor := new(big.Rat).SetInt(new(big.Int).Div(quotient.Num(), quotient.Denom()))
+		if operator.TokenType == token.PercentToken {
+			return NewDecimal(new(big.Rat).Sub(l, new(big.Rat).Mul(r, floor)), scale), nil
+		}
+		return NewDecimal(floor, scale), nil
+	case token.StarStarToken:
+		if !r.IsInt() || !r.Num().IsInt64() {
+			// Fractional exponents have no exact result
+			return math.Pow(toFloat(l), toFloat(r)), nil
+		}
+		exponent := r.Num()
+		if exponent.Sign() < 0 {
+			if l.Sign() == 0 {
+				return nil, NewRuntimeError(operator, "Division by zero.")
+			}
+			l = new(big.Rat).Inv(l)
+			exponent = new(big.Int).Neg(exponent)
+		}
+		err := checkExpBits(operator, l.Num(), exponent)
+		if err == nil {
+			err = checkExpBits(operator, l.Denom(), exponent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		numerator := new(big.Int).Exp(l.Num(), exponent, nil)
+		denominator := new(big.Int).Exp(l.Denom(), exponent, nil)
+		return NewDecimal(new(big.Rat).SetFrac(numerator, denominator), scale), nil
+	}
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+func floatArithmetic(operator token.Token, l, r float64) (interface{}, error) {
+	switch operator.TokenType {
+	case token.PlusToken:
+		return l + r, nil
+	case token.MinusToken:
+		return l - r, nil
+	case token.StarToken:
+		return l * r, nil
+	case token.SlashToken:
+		return l / r, nil
+	case token.SlashSlashToken:
+		return math.Floor(l / r), nil
+	case token.PercentToken:
+		modulo := math.Mod(l, r)
+		if modulo != 0 && (modulo < 0) != (r < 0) {
+			modulo += r
+		}
+		return modulo, nil
+	case token.StarStarToken:
+		return math.Pow(l, r), nil
+	}
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+func isInteger(value interface{}) bool {
+	kind := numberKind(value)
+	return kind == intKind || kind == bigIntKind
+}
+
+// bitwise applies a bitwise or shift operator, expecting both operands to be integers
+func bitwise(operator token.Token, left, right interface{}) (interface{}, error) {
+	if widerKind(left, right) == intKind {
+		l, r := left.(int64), right.(int64)
+		switch operator.TokenType {
+		case token.AmpersandToken:
+			return l & r, nil
+		case token.PipeToken:
+			return l | r, nil
+		case token.CaretToken:
+			return l ^ r, nil
+		case token.LessLessToken:
+			if r < 0 {
+				return nil, NewRuntimeError(operator, "Negative shift count.")
+			}
+			if l == 0 {
+				return l, nil
+			}
+			shifted := l << uint64(r)
+			if r >= 64 || shifted>>uint64(r) != l {
+				return nil, NewRuntimeError(operator, "Integer overflow.")
+			}
+			return shifted, nil
+		case token.GreaterGreaterToken:
+			if r < 0 {
+				return nil, NewRuntimeError(operator, "Negative shift count.")
+			}
+			return l >> uint64(r), nil
+		}
+		return nil, NewRuntimeError(operator, "Undefined operator.")
+	}
+
+	l, r := toBigInt(left), toBigInt(right)
+	switch operator.TokenType {
+	case token.AmpersandToken:
+		return new(big.Int).And(l, r), nil
+	case token.PipeToken:
+		return new(big.Int).Or(l, r), nil
+	case token.CaretToken:
+		return new(big.Int).Xor(l, r), nil
+	case token.LessLessToken, token.GreaterGreaterToken:
+		if r.Sign() < 0 {
+			return nil, NewRuntimeError(operator, "Negative shift count.")
+		}
+		if !r.IsInt64() || r.Int64() > math.MaxInt32 {
+			return nil, NewRuntimeError(operator, "Shift count too large.")
+		}
+		if operator.TokenType == token.LessLessToken && l.Sign() != 0 && int64(l.BitLen())+r.Int64() > maxBigIntBits {
+			return nil, NewRuntimeError(operator, "Result too large.")
+		}
+		if operator.TokenType == token.LessLessToken {
+			return new(big.Int).Lsh(l, uint(r.Int64())), nil
+		}
+		return new(big.Int).Rsh(l, uint(r.Int64())), nil
+	}
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+func complement(operator token.Token, operand interface{}) (interface{}, error) {
+	switch n := operand.(type) {
+	case int64:
+		return ^n, nil
+	case *big.Int:
+		return new(big.Int).Not(n), nil
+	}
+	return nil, NewRuntimeError(operator, "Operand must be an integer.")
+}
+
+// compare applies a comparison operator, expecting both operands to be numbers
+func compare(operator token.Token, left, right interface{}) (interface{}, error) {
+	if widerKind(left, right) == floatKind {
+		// Compared as floats so NaN is never ordered
+		l, r := toFloat(left), toFloat(right)
+		switch operator.TokenType {
+		case token.GreaterToken:
+			return l > r, nil
+		case token.GreaterEqualToken:
+			return l >= r, nil
+		case token.LessToken:
+			return l < r, nil
+		case token.LessEqualToken:
+			return l <= r, nil
+		}
+		return nil, NewRuntimeError(operator, "Undefined operator.")
+	}
+
+	c := compareExact(left, right)
+	switch operator.TokenType {
+	case token.GreaterToken:
+		return c > 0, nil
+	case token.GreaterEqualToken:
+		return c >= 0, nil
+	case token.LessToken:
+		return c < 0, nil
+	case token.LessEqualToken:
+		return c <= 0, nil
+	}
+	return nil, NewRuntimeError(operator, "Undefined operator.")
+}
+
+// compareExact orders two numbers that are not floats, returning -1, 0 or +1
+func compareExact(left, right interface{}) int {
+	switch widerKind(left, right) {
+	case intKind:
+		l, r := left.(int64), right.(int64)
+		if l < r {
+			return -1
+		} else if l > r {
+			return 1
+		}
+		return 0
+	case bigIntKind:
+		return toBigInt(left).Cmp(toBigInt(right))
+	}
+	return toDecimal(left).Cmp(toDecimal(right))
+}
+
+// compareNumbers orders any two numbers, returning -1, 0 or +1. NaN orders after every other
+// number so that sorting and extremes don't depend on where it appears.
+func compareNumbers(left, right interface{}) int {
+	if widerKind(left, right) != floatKind {
+		return compareExact(left, right)
+	}
+	l, r := toFloat(left), toFloat(right)
+	if lNaN, rNaN := math.IsNaN(l), math.IsNaN(r); lNaN || rNaN {
+		if lNaN && rNaN {
+			return 0
+		} else if lNaN {
+			return 1
+		}
+		return -1
+	}
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func negate(operator token.Token, operand interface{}) (interface{}, error) {
+	switch n := operand.(type) {
+	case int64:
+		if n == math.MinInt64 {
+			return nil, NewRuntimeError(operator, "Integer overflow.")
+		}
+		return -n, nil
+	case *big.Int:
+		return new(big.Int).Neg(n), nil
+	case *Decimal:
+		return NewDecimal(new(big.Rat).Neg(n.Rat), n.Scale), nil
+	case float64:
+		return -n, nil
+	}
+	return nil, NewRuntimeError(operator, "Operand must be a number.")
+}
+
+// numbersEqual compares two numbers by exact value, so 1 == 1.0 and 2n == 2
+func numbersEqual(a, b interface{}) bool {
+	if widerKind(a, b) == floatKind {
+		l, r := toFloat(a), toFloat(b)
+		if math.IsInf(l, 0) || math.IsNaN(l) || math.IsInf(r, 0) || math.IsNaN(r) {
+			return l == r
+		}
+		// Finite floats convert exactly, so large integers never compare equal to a nearby float
+		return compareExact(exactFloat(a), exactFloat(b)) == 0
+	}
+	return compareExact(a, b) == 0
+}
+
+// exactFloat converts a finite float to a decimal holding its exact value
+func exactFloat(value interface{}) interface{} {
+	if f, ok := value.(float64); ok {
+		return NewDecimal(new(big.Rat).SetFloat64(f), 0)
+	}
+	return value
+}
+
+// formatFloat always shows a decimal point for finite floats, keeping them distinct from integers
+func formatFloat(f float64) string {
+	s := fmt.Sprintf("%v", f)
+	if strings.ContainsAny(s, ".eIN") {
+		return s
+	}
+	return s + ".0"
+}
+
+// formatDecimal prints the exact expansion of a decimal with at least its scale of fractional digits,
+// falling back to decimalPrecision digits when the expansion does not terminate
+func formatDecimal(d *Decimal) string {
+	r := d.Rat
+	if r.IsInt() {
+		return r.FloatString(d.Scale)
+	}
+
+	// A fraction terminates when its denominator only has factors of 2 and 5
+	denominator := new(big.Int).Set(r.Denom())
+	twos, fives := 0, 0
+	two, five := big.NewInt(2), big.NewInt(5)
+	remainder := new(big.Int)
+	for remainder.Rem(denominator, two).Sign() == 0 {
+		denominator.Quo(denominator, two)
+		twos++
+	}
+	for remainder.Rem(denominator, five).Sign() == 0 {
+		denominator.Quo(denominator, five)
+		fives++
+	}
+
+	if denominator.IsInt64() && denominator.Int64() == 1 {
+		digits := twos
+		if fives > digits {
+			digits = fives
+		}
+		if d.Scale > digits {
+			digits = d.Scale
+		}
+		return r.FloatString(digits)
+	}
+
+	digits := decimalPrecision
+	if d.Scale > digits {
+		digits = d.Scale
+	}
+	s := r.FloatString(digits)
+	// Trailing zeros of the rounded expansion are dropped down to the scale
+	for strings.HasSuffix(s, "0") && len(s)-strings.Index(s, ".")-1 > d.Scale {
+		s = s[:len(s)-1]
+	}
+	return strings.TrimSuffix(s, ".")
+}
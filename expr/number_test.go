@@ -0,0 +1,74 @@
+package expr_test
+
+import "testing"
+
+func TestIntegers(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print 1 + 2;", output: "3\n"},
+		{source: "print 9007199254740993 + 0;", output: "9007199254740993\n"},
+		{source: "print 6 / 3;", output: "2\n"},
+		{source: "print 7 / 2;", output: "3.5\n"},
+		{source: "print 1 + 0.5;", output: "1.5\n"},
+		{source: "print 2.0 * 3;", output: "6.0\n"},
+		{source: "print -9223372036854775807 - 1;", output: "-9223372036854775808\n"},
+		{source: "print 1 == 1.0;", output: "true\n"},
+		{source: "print 9223372036854775807 + 1;", err: "Integer overflow."},
+		{source: "print -9223372036854775807 - 2;", err: "Integer overflow."},
+		{source: "print 4611686018427387904 * 2;", err: "Integer overflow."},
+		{source: "print 1 / 0;", err: "Division by zero."},
+	})
+}
+
+func TestBigNumbers(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print 9223372036854775807n + 1;", output: "9223372036854775808\n"},
+		{source: "print 2n ** 100;", output: "1267650600228229401496703205376\n"},
+		{source: "print 10n / 4n;", output: "2.5\n"},
+		{source: "print 0.1d + 0.2d;", output: "0.3\n"},
+		{source: "print 0.1d + 0.2d == 0.3d;", output: "true\n"},
+		{source: "print 19.99d * 3;", output: "59.97\n"},
+		{source: "print 1d / 3;", output: "0.33333333333333333333\n"},
+		{source: "print 2d ** -2;", output: "0.25\n"},
+		{source: "print 1.5d + 0.5;", output: "2.0\n"},
+		{source: "print 3n < 2.5d;", output: "false\n"},
+		// Decimals print with the larger scale of their operands
+		{source: "print 19.99d + 0.01d, 1.50d, -1.50d, 2.000d - 1;", output: "20.00 1.50 -1.50 1.000\n"},
+		{source: "print 0.10d * 0.10d, 10.00d / 3, 1.50d == 1.5d;", output: "0.01 3.33333333333333333333 true\n"},
+		{source: "var m = {1.5d: 1}; print m[1.50d], json.stringify([1.50d]), format(\"{}\", 1.50d);", output: "1 [1.50] 1.50\n"},
+		{source: "print math.abs(-1.50d), math.floor(1.50d), [1.0d], 1.0d + 1n;", output: "1.50 1 [1.0] 2.0\n"},
+		{source: "print 1n / 0;", err: "Division by zero."},
+		{source: "print 1n ** 9000000000000n;", output: "1\n"},
+		{source: "print 2n ** 9000000000000n;", err: "Result too large."},
+		{source: "print 2n ** 99999999999999999999n;", err: "Exponent too large."},
+		{source: "print 1.5d ** 9000000000000;", err: "Result too large."},
+		{source: "print 1n << 2000000;", err: "Result too large."},
+		{source: "print (1n << 64) >> 60;", output: "16\n"},
+	})
+}
+
+func TestOperators(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print 7 % 3; print -7 % 3; print 7 % -3;", output: "1\n2\n-2\n"},
+		{source: "print 7 // 2; print -7 // 2; print 7.5 // 2;", output: "3\n-4\n3.0\n"},
+		{source: "print 5.5 % 2;", output: "1.5\n"},
+		{source: "print 2 ** 10; print 2 ** -1; print 2 ** 3 ** 2;", output: "1024\n0.5\n512\n"},
+		{source: "print -2 ** 2;", output: "-4\n"},
+		{source: "print 6 & 3; print 6 | 3; print 6 ^ 3; print ~5;", output: "2\n7\n5\n-6\n"},
+		{source: "print 1 << 4; print 256 >> 4; print -16 >> 2;", output: "16\n16\n-4\n"},
+		{source: "print 2 ** 63;", err: "Integer overflow."},
+		{source: "print 1 << 63;", err: "Integer overflow."},
+		{source: "print 1 << -1;", err: "Negative shift count."},
+		{source: "print 1.5 & 1;", err: "Operands must be integers."},
+		{source: "print 7 // 0;", err: "Division by zero."},
+		{source: "print 7 % 0;", err: "Division by zero."},
+	})
+}
+
+// NaN orders after every other number, wherever it appears
+func TestNaNOrder(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print sort([math.nan, 2, 1]), sort([2, math.nan, 1]), sort([1, 2, math.nan]);", output: "[1, 2, NaN] [1, 2, NaN] [1, 2, NaN]\n"},
+		{source: "print math.min(math.nan, 2, 1), math.min(2, 1, math.nan), math.max(math.nan, 1), math.max(1, math.nan);", output: "1 1 NaN NaN\n"},
+		{source: "print math.nan < 1, math.nan > 1, format(\"{}\", -math.nan);", output: "false false NaN\n"},
+	})
+}
@@ -0,0 +1,77 @@
+package expr
+
+import (
+    "github.com/levi/holo/token"
+)
+
+type Pattern interface {}
+
+
+type AlternativePattern struct {
+    alternatives []Pattern
+}
+
+func NewAlternativePattern(alternatives []Pattern) AlternativePattern {
+    return AlternativePattern{
+        alternatives,
+    }
+}
+
+type BindingPattern struct {
+    name token.Token
+}
+
+func NewBindingPattern(name token.Token) BindingPattern {
+    return BindingPattern{
+        name,
+    }
+}
+
+type ListPattern struct {
+    bracket token.Token
+    elements []Pattern
+    rest Pattern
+}
+
+func NewListPattern(bracket token.Token, elements []Pattern, rest Pattern) ListPattern {
+    return ListPattern{
+        bracket,
+        elements,
+        rest,
+    }
+}
+
+type LiteralPattern struct {
+    value Expr
+}
+
+func NewLiteralPattern(value Expr) LiteralPattern {
+    return LiteralPattern{
+        value,
+    }
+}
+
+type MapPattern struct {
+    brace token.Token
+    keys []interface{}
+    values []Pattern
+}
+
+func NewMapPattern(brace token.Token, keys []interface{}, values []Pattern) MapPattern {
+    return MapPattern{
+        brace,
+        keys,
+        values,
+    }
+}
+
+type WildcardPattern struct {
+    name token.Token
+}
+
+func NewWildcardPattern(name token.Token) WildcardPattern {
+    return WildcardPattern{
+        name,
+    }
+}
+
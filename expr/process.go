@@ -0,0 +1,147 @@
+package expr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/levi/holo/token"
+)
+
+// The os module exposes the script arguments, the environment, exiting and, when enabled, running
+// subprocesses. The io module reads standard input.
+var osModule = DefineNativeModule("os")
+
+// subprocesses allows os.run, which embedders must opt in to
+var subprocesses = false
+
+func init() {
+	osModule.Export("args", NewList([]interface{}{}))
+	osModule.Export("env", environmentMap())
+	osModule.exportNative("exit", []string{"code"}, 0, false, osExit)
+	osModule.exportNative("run", []string{"command", "input"}, 1, false, osRun)
+
+	m := DefineNativeModule("io")
+	m.exportNative("readLine", nil, 0, false, ioReadLine)
+	m.exportNative("readAll", nil, 0, false, ioReadAll)
+}
+
+// SetArgs sets os.args to the arguments following the script path
+func SetArgs(args []string) {
+	elements := make([]interface{}, len(args))
+	for i, arg := range args {
+		elements[i] = arg
+	}
+	list := NewList(elements)
+	list.Frozen = true
+	osModule.Export("args", list)
+}
+
+// EnableSubprocesses allows scripts to run other programs with os.run
+func EnableSubprocesses(enabled bool) {
+	subprocesses = enabled
+}
+
+// environmentMap snapshots the environment variables into a frozen map
+func environmentMap() *Map {
+	m := NewMap()
+	for _, variable := range os.Environ() {
+		pair := strings.SplitN(variable, "=", 2)
+		if len(pair) == 2 {
+			m.Set(pair[0], pair[1])
+		}
+	}
+	m.Frozen = true
+	return m
+}
+
+// Exit is returned by Interpret when a script calls os.exit. It unwinds like an error but can't be caught.
+type Exit struct {
+	Code int
+}
+
+func (e *Exit) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func osExit(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	code := int64(0)
+	if !defaulted(arguments, 0) {
+		var err error
+		code, err = intArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, &Exit{int(code)}
+}
+
+// osRun runs a program given as a list of its path and arguments, returning a map of its stdout, stderr and exit code
+func osRun(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	if !subprocesses {
+		return nil, NewRuntimeError(paren, "Running subprocesses is not enabled.")
+	}
+	command, err := listArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(command.Elements) == 0 {
+		return nil, NewRuntimeError(paren, "Command must not be empty.")
+	}
+	args := make([]string, len(command.Elements))
+	for i, element := range command.Elements {
+		arg, ok := element.(string)
+		if !ok {
+			return nil, argumentError(paren, signature, 0, "a list of strings")
+		}
+		args[i] = arg
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	if !defaulted(arguments, 1) {
+		stdin, err := stringArgument(paren, signature, arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// A non-zero exit is reported in the result rather than as an error
+	err = cmd.Run()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+	}
+
+	result := NewMap()
+	result.Set("stdout", stdout.String())
+	result.Set("stderr", stderr.String())
+	result.Set("code", int64(cmd.ProcessState.ExitCode()))
+	return result, nil
+}
+
+// ioReadLine reads a line without its line ending, or nil at the end of input
+func ioReadLine(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	line, err := input.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+func ioReadAll(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+	}
+	return string(data), nil
+}
@@ -0,0 +1,57 @@
+package expr_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/levi/holo/expr"
+)
+
+func TestOSModule(t *testing.T) {
+	expr.SetArgs([]string{"one", "--two"})
+	defer expr.SetArgs(nil)
+	path, _ := os.LookupEnv("PATH")
+
+	runScripts(t, []scriptTest{
+		{source: "print os.args; print os.args[1];", output: "[\"one\", \"--two\"]\n--two\n"},
+		{source: "os.args[0] = \"x\";", err: "Cannot modify a frozen list."},
+		{source: "print os.env[\"PATH\"] + \"|\"; print \"HOLO_TEST_MISSING\" in os.env;", output: path + "|\nfalse\n"},
+		{source: "os.env[\"X\"] = \"y\";", err: "Cannot modify a frozen map."},
+		{source: "print 1; os.exit(3); print 2;", output: "1\n", err: "exit status 3"},
+		{source: "os.exit();", err: "exit status 0"},
+		// Exiting can't be caught, but finally blocks still run
+		{source: "try { os.exit(1); } catch (e) { print \"caught\"; } finally { print \"finally\"; }", output: "finally\n", err: "exit status 1"},
+		{source: "fn f() { try { os.exit(2); } finally { return 1; } } print f();", err: "exit status 2"},
+		{source: "try { os.exit(2); } finally { throw \"finally\"; }", err: "exit status 2"},
+		{source: "try { try { os.exit(2); } catch (e) { print \"caught\"; } } finally { os.exit(3); }", err: "exit status 2"},
+		{source: "while (true) { try { os.exit(2); } finally { break; } } print \"after\";", err: "exit status 2"},
+		{source: "os.exit(\"1\");", err: "Argument 'code' of os.exit() must be an integer."},
+		{source: "os.run([\"true\"]);", err: "Running subprocesses is not enabled."},
+	})
+}
+
+func TestIOModule(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: "print io.readLine(); print io.readLine(); print io.readLine(); print io.readLine();", input: "a\r\nb\n\n", output: "a\nb\n\nnil\n"},
+		{source: "print io.readLine(); print io.readLine();", input: "last", output: "last\nnil\n"},
+		{source: "print io.readLine(); print io.readAll();", input: "a\nb\nc", output: "a\nb\nc\n"},
+		{source: "print io.readAll() + \"|\"; print io.readLine();", input: "", output: "|\nnil\n"},
+	})
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh to run")
+	}
+	expr.EnableSubprocesses(true)
+	defer expr.EnableSubprocesses(false)
+
+	runScripts(t, []scriptTest{
+		{source: "print os.run([\"sh\", \"-c\", \"echo out; echo err >&2; exit 2\"]);", output: "{\"stdout\": \"out\\n\", \"stderr\": \"err\\n\", \"code\": 2}\n"},
+		{source: "print os.run([\"sh\", \"-c\", \"tr a-z A-Z\"], \"hi\")[\"stdout\"];", output: "HI\n"},
+		{source: "os.run([]);", err: "Command must not be empty."},
+		{source: "os.run([\"sh\", 1]);", err: "Argument 'command' of os.run() must be a list of strings."},
+		{source: "os.run([\"holo-no-such-program\"]);", err: "os.run() failed: exec: \"holo-no-such-program\": executable file not found in $PATH."},
+	})
+}
@@ -0,0 +1,116 @@
+package expr
+
+import (
+	cryptorand "crypto/rand"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/levi/holo/token"
+)
+
+// The random module draws from a source owned by the calling module, so every run starts from a fresh
+// sequence. Scripts can seed it with random.seed(n) and embedders with Module.SeedRandom so runs are
+// reproducible. uuid() reads crypto/rand instead, since identifiers shouldn't be predictable.
+//
+//	random.int(min, max)  integer between min and max inclusive
+//	random.float()        float in [0, 1)
+//	random.choice(xs)     random element of a non-empty list
+//	random.shuffle(xs)    shuffles a list in place, returning it
+//	random.seed(n)        restarts the sequence from seed n
+//	uuid()                random version 4 UUID
+func newRandomSource() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// SeedRandom restarts the module's sequence of random values from seed
+func (m *Module) SeedRandom(seed int64) {
+	m.random = rand.New(rand.NewSource(seed))
+}
+
+func init() {
+	m := DefineNativeModule("random")
+	m.exportNative("int", []string{"min", "max"}, 2, false, randomInt)
+	m.exportNative("float", nil, 0, false, randomFloat)
+	m.exportNative("choice", []string{"list"}, 1, false, randomChoice)
+	m.exportNative("shuffle", []string{"list"}, 1, false, randomShuffle)
+	m.exportNative("seed", []string{"n"}, 1, false, randomSeed)
+
+	DefineNative(NewNative(NewSignature("uuid", nil, 0, false), uuid))
+}
+
+func randomInt(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	min, err := intArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	max, err := intArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	if min > max {
+		return nil, NewRuntimeError(paren, "Minimum must not be greater than maximum.")
+	}
+	// The span overflows to zero or below only when it covers every int64
+	span := uint64(max-min) + 1
+	if span == 0 {
+		return int64(currentModule.random.Uint64()), nil
+	}
+	if span < 1<<63 {
+		return min + currentModule.random.Int63n(int64(span)), nil
+	}
+	for {
+		n := currentModule.random.Uint64()
+		if n < span {
+			return min + int64(n), nil
+		}
+	}
+}
+
+func randomFloat(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return currentModule.random.Float64(), nil
+}
+
+func randomChoice(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	list, err := listArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Elements) == 0 {
+		return nil, NewRuntimeError(paren, "Cannot choose from an empty list.")
+	}
+	return list.Elements[currentModule.random.Intn(len(list.Elements))], nil
+}
+
+func randomShuffle(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	list, err := listArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if list.Frozen {
+		return nil, NewRuntimeError(paren, "Cannot modify a frozen list.")
+	}
+	currentModule.random.Shuffle(len(list.Elements), func(i, j int) {
+		list.Elements[i], list.Elements[j] = list.Elements[j], list.Elements[i]
+	})
+	return list, nil
+}
+
+func randomSeed(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	seed, err := intArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	currentModule.SeedRandom(seed)
+	return nil, nil
+}
+
+func uuid(paren token.Token, arguments []interface{}) (interface{}, error) {
+	var b [16]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("uuid() failed: %s.", err))
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
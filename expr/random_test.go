@@ -0,0 +1,102 @@
+package expr_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/levi/holo/expr"
+)
+
+func TestRandom(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print random.int(5, 5), random.choice([7]);`, output: "5 7\n"},
+		{source: `var n = random.int(-2, 2); print n >= -2, n <= 2;`, output: "true true\n"},
+		{source: `var f = random.float(); print f >= 0, f < 1;`, output: "true true\n"},
+		{source: `print random.int(-9223372036854775807 - 1, 9223372036854775807) != nil;`, output: "true\n"},
+		// Spans of exactly 2^63 don't fit Int63n
+		{source: `print random.int(0, 9223372036854775807) >= 0;`, output: "true\n"},
+		{source: `print random.int(-9223372036854775807 - 1, -1) < 0;`, output: "true\n"},
+		// shuffle permutes the list in place
+		{source: `var xs = [1, 2, 3, 4]; print random.shuffle(xs) == xs, sort(xs);`, output: "true [1, 2, 3, 4]\n"},
+		{source: `print uuid() != uuid();`, output: "true\n"},
+		{source: `print random.int(2, 1);`, err: "Minimum must not be greater than maximum."},
+		{source: `print random.int(1.5, 2);`, err: "Argument 'min' of random.int() must be an integer."},
+		{source: `print random.choice([]);`, err: "Cannot choose from an empty list."},
+		{source: `print random.choice(1);`, err: "Argument 'list' of random.choice() must be a list."},
+		{source: `print random.shuffle(freeze([1]));`, err: "Cannot modify a frozen list."},
+		{source: `random.seed("a");`, err: "Argument 'n' of random.seed() must be an integer."},
+	})
+}
+
+// Seeding from a script or an embedder repeats the same values
+func TestRandomSeed(t *testing.T) {
+	const source = `print random.int(1, 1000000), random.float(), random.choice([1, 2, 3, 4, 5]), random.shuffle([1, 2, 3, 4, 5]);`
+
+	first, err := run(t, "random.seed(42); "+source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := run(t, "random.seed(42); "+source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("seed 42 printed %q, then %q", first, second)
+	}
+
+	embedded, err := execute(t, source, func(statements []expr.Stmt) error {
+		m := expr.NewModule("")
+		m.SeedRandom(42)
+		return expr.RunModule(m, statements)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if embedded != first {
+		t.Errorf("SeedRandom(42) printed %q, want %q", embedded, first)
+	}
+
+	other, err := run(t, "random.seed(43); "+source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Errorf("seeds 42 and 43 both printed %q", first)
+	}
+}
+
+// A seed lasts for the module it was set in, so later runs start from a fresh sequence
+func TestRandomSeedPerRun(t *testing.T) {
+	const source = `print random.int(1, 1000000000), random.int(1, 1000000000);`
+
+	seeded, err := run(t, "random.seed(42); "+source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh == seeded {
+		t.Errorf("a run after random.seed(42) repeated %q", seeded)
+	}
+}
+
+// uuid() doesn't follow the seed of the random module
+func TestUUID(t *testing.T) {
+	version4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\n$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		output, err := run(t, "random.seed(1); print uuid();")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !version4.MatchString(output) {
+			t.Errorf("printed %q, want a version 4 UUID", output)
+		}
+		if seen[output] {
+			t.Errorf("printed %q twice", output)
+		}
+		seen[output] = true
+	}
+}
@@ -0,0 +1,215 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/levi/holo/token"
+)
+
+// Regex is a compiled RE2 regular expression. Scripts call methods on it:
+//
+//	match(s)                 whether the expression matches anywhere in s
+//	find(s)                  the first match, or nil
+//	findAll(s, limit?)       list of matches, at most limit when given
+//	captures(s)              list of the first match and its groups, or nil; unmatched groups are nil
+//	capturesAll(s)           list of captures for every match
+//	namedCaptures(s)         map of named group to text for the first match, or nil
+//	replace(s, replacement)  s with every match replaced. A string replacement may refer to groups
+//	                         as $1 or ${name}; a function is called with the captures of each match.
+//
+// The re module has the same functions taking a pattern first, such as re.find(pattern, s).
+type Regex struct {
+	regexp *regexp.Regexp
+}
+
+func (r *Regex) String() string {
+	return "<regex " + r.regexp.String() + ">"
+}
+
+// regexMethod is a native bound to the regex it is called on
+type regexMethod struct {
+	params   []string
+	required int
+	function func(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error)
+}
+
+var regexMethods = map[string]regexMethod{
+	"match":         {[]string{"s"}, 1, regexMatch},
+	"find":          {[]string{"s"}, 1, regexFind},
+	"findAll":       {[]string{"s", "limit"}, 1, regexFindAll},
+	"captures":      {[]string{"s"}, 1, regexCaptures},
+	"capturesAll":   {[]string{"s"}, 1, regexCapturesAll},
+	"namedCaptures": {[]string{"s"}, 1, regexNamedCaptures},
+	"replace":       {[]string{"s", "replacement"}, 2, regexReplace},
+}
+
+func init() {
+	m := DefineNativeModule("re")
+	m.exportNative("compile", []string{"pattern"}, 1, false, func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		return compileArgument(paren, signature, arguments)
+	})
+
+	// Each method is also a module function compiling its pattern on every call
+	for name, method := range regexMethods {
+		method := method
+		params := append([]string{"pattern"}, method.params...)
+		m.exportNative(name, params, method.required+1, false, func(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+			r, err := compileArgument(paren, signature, arguments)
+			if err != nil {
+				return nil, err
+			}
+			return method.function(r, paren, NewSignature(signature.Name, signature.Params[1:], method.required, false), arguments[1:])
+		})
+	}
+}
+
+// compileArgument compiles the pattern in the first argument
+func compileArgument(paren token.Token, signature *Signature, arguments []interface{}) (*Regex, error) {
+	pattern, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		message := strings.TrimPrefix(err.Error(), "error parsing regexp: ")
+		return nil, NewRuntimeError(paren, fmt.Sprintf("Invalid regular expression '%s': %s.", pattern, message))
+	}
+	return &Regex{compiled}, nil
+}
+
+// regexProperty binds the named method to a regex
+func regexProperty(r *Regex, name token.Token) (interface{}, error) {
+	method, ok := regexMethods[name.Lexeme]
+	if !ok {
+		return nil, NewRuntimeError(name, "Undefined regex method '"+name.Lexeme+"'.")
+	}
+	signature := NewSignature(name.Lexeme, method.params, method.required, false)
+	return NewNative(signature, func(paren token.Token, arguments []interface{}) (interface{}, error) {
+		return method.function(r, paren, signature, arguments)
+	}), nil
+}
+
+// captureList lists the text of a match and its groups from submatch indices, with nil for unmatched groups
+func captureList(s string, indices []int) *List {
+	elements := make([]interface{}, len(indices)/2)
+	for i := range elements {
+		start, end := indices[2*i], indices[2*i+1]
+		if start >= 0 {
+			elements[i] = s[start:end]
+		}
+	}
+	return NewList(elements)
+}
+
+func regexMatch(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return r.regexp.MatchString(s), nil
+}
+
+func regexFind(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	indices := r.regexp.FindStringIndex(s)
+	if indices == nil {
+		return nil, nil
+	}
+	return s[indices[0]:indices[1]], nil
+}
+
+func regexFindAll(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	limit := int64(-1)
+	if !defaulted(arguments, 1) {
+		limit, err = intArgument(paren, signature, arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	elements := []interface{}{}
+	for _, match := range r.regexp.FindAllString(s, int(limit)) {
+		elements = append(elements, match)
+	}
+	return NewList(elements), nil
+}
+
+func regexCaptures(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	indices := r.regexp.FindStringSubmatchIndex(s)
+	if indices == nil {
+		return nil, nil
+	}
+	return captureList(s, indices), nil
+}
+
+func regexCapturesAll(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	elements := []interface{}{}
+	for _, indices := range r.regexp.FindAllStringSubmatchIndex(s, -1) {
+		elements = append(elements, captureList(s, indices))
+	}
+	return NewList(elements), nil
+}
+
+func regexNamedCaptures(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	indices := r.regexp.FindStringSubmatchIndex(s)
+	if indices == nil {
+		return nil, nil
+	}
+	captures := captureList(s, indices).Elements
+	m := NewMap()
+	for i, name := range r.regexp.SubexpNames() {
+		if name != "" {
+			m.Set(name, captures[i])
+		}
+	}
+	return m, nil
+}
+
+// regexReplace replaces every match, expanding groups in a string replacement or calling a replacement function
+func regexReplace(r *Regex, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	s, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if replacement, ok := arguments[1].(string); ok {
+		return r.regexp.ReplaceAllString(s, replacement), nil
+	}
+	callable, ok := arguments[1].(Callable)
+	if !ok {
+		return nil, argumentError(paren, signature, 1, "a string or function")
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, indices := range r.regexp.FindAllStringSubmatchIndex(s, -1) {
+		result, err := call(paren, callable, []interface{}{captureList(s, indices)}, []string{""})
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(s[last:indices[0]])
+		b.WriteString(stringify(result))
+		last = indices[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
@@ -0,0 +1,26 @@
+package expr_test
+
+import "testing"
+
+func TestRegex(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `var r = re.compile("\d+"); print r; print r.match("a1"); print r.match("ab");`, output: "<regex \\d+>\ntrue\nfalse\n"},
+		{source: `print re.find("\d+", "ab12cd34"); print re.find("\d+", "abc");`, output: "12\nnil\n"},
+		{source: `print re.findAll("\d+", "a1b22c333"); print re.findAll("\d+", "a1b22c333", 2); print re.findAll("x", "abc");`, output: "[\"1\", \"22\", \"333\"]\n[\"1\", \"22\"]\n[]\n"},
+		{source: `print re.captures("(\w+)@(\w+)?", "me@ x"); print re.captures("z", "abc");`, output: "[\"me@\", \"me\", nil]\nnil\n"},
+		{source: `print re.capturesAll("(\w)(\d)", "a1 b2");`, output: "[[\"a1\", \"a\", \"1\"], [\"b2\", \"b\", \"2\"]]\n"},
+		{source: `print re.namedCaptures("(?P<key>\w+)=(?P<value>\w*)", "k=v"); print re.namedCaptures("(?P<a>x)", "y");`, output: "{\"key\": \"k\", \"value\": \"v\"}\nnil\n"},
+		{source: `print re.replace("(\w+)@(\w+)", "me@host", "$2 at ${1}");`, output: "host at me\n"},
+		{source: `fn upper(m) { return m[0].upper(); } print re.replace("[aeiou]", "banana", upper);`, output: "bAnAnA\n"},
+		{source: `fn count(m) { return m[0].len(); } print re.compile("\w+").replace("one three", count);`, output: "3 5\n"},
+		// Matching is by runes
+		{source: `print re.findAll(".", "hé"); print re.find("é+", "aéé");`, output: "[\"h\", \"é\"]\néé\n"},
+		{source: `print re.compile("(");`, err: "Invalid regular expression '(': missing closing ): `(`."},
+		{source: `print re.find("(?=x)", "x");`, err: "Invalid regular expression '(?=x)': invalid or unsupported Perl syntax: `(?=`."},
+		{source: `print re.replace("a", "a", 1);`, err: "Argument 'replacement' of re.replace() must be a string or function."},
+		{source: `fn boom(m) { throw "boom"; } print re.replace("a", "a", boom);`, err: "Uncaught exception: boom"},
+		{source: `print re.find(1, "a");`, err: "Argument 'pattern' of re.find() must be a string."},
+		{source: `print re.compile("a").find(1);`, err: "Argument 's' of find() must be a string."},
+		{source: `print re.compile("a").search("a");`, err: "Undefined regex method 'search'."},
+	})
+}
@@ -0,0 +1,19 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+type ResolveError struct {
+	Token   token.Token
+	Message string
+}
+
+func NewResolveError(token token.Token, message string) *ResolveError {
+	return &ResolveError{
+		token,
+		message,
+	}
+}
+
+func (e *ResolveError) Error() string {
+	return e.Message
+}
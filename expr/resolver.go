@@ -0,0 +1,244 @@
+package expr
+
+import "github.com/levi/holo/token"
+
+// resolver statically walks the AST mirroring the interpreter's scopes, recording for each
+// declared name whether it is a constant so assignments to and redeclarations of constants are
+// found before running
+type resolver struct {
+	scopes []map[string]bool
+	errors []*ResolveError
+}
+
+// Resolve checks statements before they are interpreted, reporting assignments to constants and
+// declarations replacing a constant of the same scope.
+// Names declared by earlier runs are unknown here and checked by the interpreter instead.
+func Resolve(statements []Stmt) []*ResolveError {
+	r := new(resolver)
+	r.beginScope()
+	r.statements(statements)
+	return r.errors
+}
+
+func (r *resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]bool))
+}
+
+func (r *resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+// declare records a name in the innermost scope, reporting a redeclaration of a constant
+func (r *resolver) declare(name token.Token, constant bool) {
+	scope := r.scopes[len(r.scopes)-1]
+	if scope[name.Lexeme] {
+		r.errors = append(r.errors, NewResolveError(name, "Cannot redeclare constant '"+name.Lexeme+"'."))
+	}
+	scope[name.Lexeme] = constant
+}
+
+func (r *resolver) declarePattern(pattern Pattern) {
+	for _, name := range patternBindings(pattern) {
+		r.declare(name, false)
+	}
+}
+
+// assign reports an assignment to a name which resolves to a constant
+func (r *resolver) assign(name token.Token) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if constant, ok := r.scopes[i][name.Lexeme]; ok {
+			if constant {
+				r.errors = append(r.errors, NewResolveError(name, "Cannot assign to constant '"+name.Lexeme+"'."))
+			}
+			return
+		}
+	}
+}
+
+func (r *resolver) statements(statements []Stmt) {
+	for _, s := range statements {
+		r.statement(s)
+	}
+}
+
+// scoped resolves statements within a new scope, declaring names first
+func (r *resolver) scoped(statements []Stmt, names ...token.Token) {
+	r.beginScope()
+	for _, name := range names {
+		r.declare(name, false)
+	}
+	r.statements(statements)
+	r.endScope()
+}
+
+func (r *resolver) statement(statement Stmt) {
+	switch s := statement.(type) {
+	case Block:
+		r.scoped(s.statements)
+	case Const:
+		r.expression(s.initializer)
+		r.declare(s.name, true)
+	case Export:
+		r.statement(s.declaration)
+	case Expression:
+		r.expression(s.expression)
+	case Fn:
+		r.declare(s.name, false)
+		r.beginScope()
+		for i, param := range s.params {
+			r.expression(s.defaults[i])
+			r.declare(param, false)
+		}
+		r.statements(s.body)
+		r.endScope()
+	case ForIn:
+		r.expression(s.iterable)
+		r.scoped([]Stmt{s.body}, s.names...)
+	case If:
+		r.expression(s.condition)
+		r.statement(s.thenBranch)
+		r.statement(s.elseBranch)
+	case Import:
+		r.declare(s.name, false)
+	case Print:
+		r.expressions(s.expressions)
+		r.expression(s.sep)
+		r.expression(s.end)
+	case Return:
+		r.expression(s.value)
+	case Throw:
+		r.expression(s.value)
+	case Try:
+		r.scoped(s.body)
+		if s.catchBody != nil {
+			r.scoped(s.catchBody, s.name)
+		}
+		r.scoped(s.finallyBody)
+	case Var:
+		r.expression(s.initializer)
+		r.declare(s.name, false)
+	case VarPattern:
+		r.expression(s.initializer)
+		r.declarePattern(s.pattern)
+	case While:
+		r.expression(s.condition)
+		r.statement(s.body)
+	}
+}
+
+func (r *resolver) expression(expression Expr) {
+	switch e := expression.(type) {
+	case Assign:
+		r.expression(e.value)
+		r.assign(e.name)
+	case AssignList:
+		r.expression(e.value)
+		r.assignTargets(e.targets)
+	case Binary:
+		r.expression(e.left)
+		r.expression(e.right)
+	case Call:
+		r.expression(e.callee)
+		r.expressions(e.arguments)
+	case Conditional:
+		r.expression(e.condition)
+		r.expression(e.thenBranch)
+		r.expression(e.elseBranch)
+	case Get:
+		r.expression(e.object)
+	case Grouping:
+		r.expression(e.expression)
+	case Increment:
+		if v, ok := e.target.(Variable); ok {
+			r.assign(v.name)
+		}
+		r.expression(e.target)
+	case Index:
+		r.expression(e.object)
+		r.expression(e.index)
+	case IndexSet:
+		r.expression(e.object)
+		r.expression(e.index)
+		r.expression(e.value)
+	case ListLiteral:
+		r.expressions(e.elements)
+	case Logical:
+		r.expression(e.left)
+		r.expression(e.right)
+	case MapLiteral:
+		r.expressions(e.keys)
+		r.expressions(e.values)
+	case Match:
+		r.expression(e.subject)
+		for i, pattern := range e.patterns {
+			r.beginScope()
+			r.declarePattern(pattern)
+			r.expression(e.guards[i])
+			r.expression(e.bodies[i])
+			r.endScope()
+		}
+	case RangeLiteral:
+		r.expression(e.start)
+		r.expression(e.end)
+	case Slice:
+		r.expression(e.object)
+		r.expression(e.start)
+		r.expression(e.end)
+	case Unary:
+		r.expression(e.right)
+	}
+}
+
+// assignTargets resolves the targets of a destructuring assignment
+func (r *resolver) assignTargets(targets []Expr) {
+	for _, target := range targets {
+		switch t := target.(type) {
+		case Variable:
+			r.assign(t.name)
+		case ListLiteral:
+			r.assignTargets(t.elements)
+		default:
+			r.expression(t)
+		}
+	}
+}
+
+func (r *resolver) expressions(expressions []Expr) {
+	for _, e := range expressions {
+		r.expression(e)
+	}
+}
+
+// patternNames lists the variables bound by a pattern
+func patternNames(pattern Pattern) []string {
+	names := []string{}
+	for _, name := range patternBindings(pattern) {
+		names = append(names, name.Lexeme)
+	}
+	return names
+}
+
+// patternBindings lists the name tokens of the variables bound by a pattern
+func patternBindings(pattern Pattern) []token.Token {
+	names := []token.Token{}
+	switch p := pattern.(type) {
+	case AlternativePattern:
+		for _, alternative := range p.alternatives {
+			names = append(names, patternBindings(alternative)...)
+		}
+	case BindingPattern:
+		names = append(names, p.name)
+	case ListPattern:
+		for _, element := range p.elements {
+			names = append(names, patternBindings(element)...)
+		}
+		if p.rest != nil {
+			names = append(names, patternBindings(p.rest)...)
+		}
+	case MapPattern:
+		for _, value := range p.values {
+			names = append(names, patternBindings(value)...)
+		}
+	}
+	return names
+}
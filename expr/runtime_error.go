@@ -5,12 +5,14 @@ import "github.com/levi/holo/token"
 type RuntimeError struct {
 	Token   token.Token
 	Message string
+	Stack   []string
 }
 
 func NewRuntimeError(token token.Token, message string) *RuntimeError {
 	return &RuntimeError{
 		token,
 		message,
+		stackTrace(token.Line),
 	}
 }
 
@@ -1,8 +1,70 @@
 package expr
 
+import (
+    "github.com/levi/holo/token"
+)
+
 type Stmt interface {}
 
 
+type Block struct {
+    statements []Stmt
+}
+
+func NewBlock(statements []Stmt) Block {
+    return Block{
+        statements,
+    }
+}
+
+type Break struct {
+    keyword token.Token
+    label string
+}
+
+func NewBreak(keyword token.Token, label string) Break {
+    return Break{
+        keyword,
+        label,
+    }
+}
+
+type Const struct {
+    name token.Token
+    initializer Expr
+}
+
+func NewConst(name token.Token, initializer Expr) Const {
+    return Const{
+        name,
+        initializer,
+    }
+}
+
+type Continue struct {
+    keyword token.Token
+    label string
+}
+
+func NewContinue(keyword token.Token, label string) Continue {
+    return Continue{
+        keyword,
+        label,
+    }
+}
+
+type Export struct {
+    keyword token.Token
+    declaration Stmt
+}
+
+func NewExport(keyword token.Token, declaration Stmt) Export {
+    return Export{
+        keyword,
+        declaration,
+    }
+}
+
 type Expression struct {
     expression Expr
 }
@@ -13,13 +75,163 @@ func NewExpression(expression Expr) Expression {
     }
 }
 
+type Fn struct {
+    name token.Token
+    params []token.Token
+    defaults []Expr
+    variadic bool
+    body []Stmt
+}
+
+func NewFn(name token.Token, params []token.Token, defaults []Expr, variadic bool, body []Stmt) Fn {
+    return Fn{
+        name,
+        params,
+        defaults,
+        variadic,
+        body,
+    }
+}
+
+type ForIn struct {
+    names []token.Token
+    keyword token.Token
+    iterable Expr
+    body Stmt
+    label string
+}
+
+func NewForIn(names []token.Token, keyword token.Token, iterable Expr, body Stmt, label string) ForIn {
+    return ForIn{
+        names,
+        keyword,
+        iterable,
+        body,
+        label,
+    }
+}
+
+type If struct {
+    condition Expr
+    thenBranch Stmt
+    elseBranch Stmt
+}
+
+func NewIf(condition Expr, thenBranch Stmt, elseBranch Stmt) If {
+    return If{
+        condition,
+        thenBranch,
+        elseBranch,
+    }
+}
+
+type Import struct {
+    keyword token.Token
+    path string
+    name token.Token
+}
+
+func NewImport(keyword token.Token, path string, name token.Token) Import {
+    return Import{
+        keyword,
+        path,
+        name,
+    }
+}
+
 type Print struct {
-    expression Expr
+    keyword token.Token
+    expressions []Expr
+    sep Expr
+    end Expr
 }
 
-func NewPrint(expression Expr) Print {
+func NewPrint(keyword token.Token, expressions []Expr, sep Expr, end Expr) Print {
     return Print{
-        expression,
+        keyword,
+        expressions,
+        sep,
+        end,
+    }
+}
+
+type Return struct {
+    keyword token.Token
+    value Expr
+}
+
+func NewReturn(keyword token.Token, value Expr) Return {
+    return Return{
+        keyword,
+        value,
+    }
+}
+
+type Throw struct {
+    keyword token.Token
+    value Expr
+}
+
+func NewThrow(keyword token.Token, value Expr) Throw {
+    return Throw{
+        keyword,
+        value,
+    }
+}
+
+type Try struct {
+    body []Stmt
+    name token.Token
+    catchBody []Stmt
+    finallyBody []Stmt
+}
+
+func NewTry(body []Stmt, name token.Token, catchBody []Stmt, finallyBody []Stmt) Try {
+    return Try{
+        body,
+        name,
+        catchBody,
+        finallyBody,
+    }
+}
+
+type Var struct {
+    name token.Token
+    initializer Expr
+}
+
+func NewVar(name token.Token, initializer Expr) Var {
+    return Var{
+        name,
+        initializer,
+    }
+}
+
+type VarPattern struct {
+    keyword token.Token
+    pattern Pattern
+    initializer Expr
+}
+
+func NewVarPattern(keyword token.Token, pattern Pattern, initializer Expr) VarPattern {
+    return VarPattern{
+        keyword,
+        pattern,
+        initializer,
+    }
+}
+
+type While struct {
+    condition Expr
+    body Stmt
+    label string
+}
+
+func NewWhile(condition Expr, body Stmt, label string) While {
+    return While{
+        condition,
+        body,
+        label,
     }
 }
 
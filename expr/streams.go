@@ -0,0 +1,27 @@
+package expr
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// The standard streams of scripts. Embedders can replace them, such as to capture output in tests.
+var input = bufio.NewReader(os.Stdin)
+var output io.Writer = os.Stdout
+var errorOutput io.Writer = os.Stderr
+
+// SetInput replaces standard input, read by the io module
+func SetInput(r io.Reader) {
+	input = bufio.NewReader(r)
+}
+
+// SetOutput replaces standard output, written by print
+func SetOutput(w io.Writer) {
+	output = w
+}
+
+// SetErrorOutput replaces standard error, written by eprint
+func SetErrorOutput(w io.Writer) {
+	errorOutput = w
+}
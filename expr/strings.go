@@ -0,0 +1,190 @@
+package expr
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/levi/holo/token"
+)
+
+// String methods are called on string values, as in s.upper(). Lengths and indices count runes.
+//
+//	len()                      number of runes
+//	upper(), lower()           change case
+//	trim(chars?)               strip whitespace, or any of chars, from both ends
+//	split(sep)                 list of the substrings between each sep, or of runes if sep is ""
+//	join(list)                 elements of list joined with the string between them
+//	replace(old, new, count?)  replace the first count occurrences of old, or all of them
+//	contains(sub)              whether sub occurs in the string
+//	startsWith(prefix)         whether the string begins with prefix
+//	endsWith(suffix)           whether the string ends with suffix
+//	indexOf(sub)               rune index of the first sub, or -1
+//	repeat(n)                  the string repeated n times
+//	chars()                    list of runes as one character strings
+//	format(...values)          substitute values into {} fields, as described in format.go
+
+// maxRepeatLength bounds the length in bytes of a string built by repeat(), so huge counts fail with a runtime error
+const maxRepeatLength = 1 << 30
+
+// stringMethod is a native bound to the string it is called on
+type stringMethod struct {
+	signature *Signature
+	function  func(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error)
+}
+
+var stringMethods = map[string]stringMethod{
+	"len":        {NewSignature("len", nil, 0, false), stringLen},
+	"upper":      {NewSignature("upper", nil, 0, false), stringUpper},
+	"lower":      {NewSignature("lower", nil, 0, false), stringLower},
+	"trim":       {NewSignature("trim", []string{"chars"}, 0, false), stringTrim},
+	"split":      {NewSignature("split", []string{"sep"}, 1, false), stringSplit},
+	"join":       {NewSignature("join", []string{"list"}, 1, false), stringJoin},
+	"replace":    {NewSignature("replace", []string{"old", "new", "count"}, 2, false), stringReplace},
+	"contains":   {NewSignature("contains", []string{"sub"}, 1, false), stringContains},
+	"startsWith": {NewSignature("startsWith", []string{"prefix"}, 1, false), stringStartsWith},
+	"endsWith":   {NewSignature("endsWith", []string{"suffix"}, 1, false), stringEndsWith},
+	"indexOf":    {NewSignature("indexOf", []string{"sub"}, 1, false), stringIndexOf},
+	"repeat":     {NewSignature("repeat", []string{"n"}, 1, false), stringRepeat},
+	"chars":      {NewSignature("chars", nil, 0, false), stringChars},
+	"format":     {NewSignature("format", []string{"values"}, 0, true), stringFormat},
+}
+
+// stringProperty binds the named method to a string
+func stringProperty(s string, name token.Token) (interface{}, error) {
+	method, ok := stringMethods[name.Lexeme]
+	if !ok {
+		return nil, NewRuntimeError(name, "Undefined string method '"+name.Lexeme+"'.")
+	}
+	return NewNative(method.signature, func(paren token.Token, arguments []interface{}) (interface{}, error) {
+		return method.function(s, paren, method.signature, arguments)
+	}), nil
+}
+
+func stringLen(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return int64(utf8.RuneCountInString(s)), nil
+}
+
+func stringUpper(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return strings.ToUpper(s), nil
+}
+
+func stringLower(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return strings.ToLower(s), nil
+}
+
+func stringTrim(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	if defaulted(arguments, 0) {
+		return strings.TrimSpace(s), nil
+	}
+	chars, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Trim(s, chars), nil
+}
+
+func stringSplit(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	sep, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(s, sep)
+	elements := make([]interface{}, len(parts))
+	for i, part := range parts {
+		elements[i] = part
+	}
+	return NewList(elements), nil
+}
+
+func stringJoin(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	list, err := listArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]string, len(list.Elements))
+	for i, element := range list.Elements {
+		parts[i] = stringify(element)
+	}
+	return strings.Join(parts, s), nil
+}
+
+func stringReplace(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	old, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := stringArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	count := int64(-1)
+	if !defaulted(arguments, 2) {
+		count, err = intArgument(paren, signature, arguments, 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return strings.Replace(s, old, replacement, int(count)), nil
+}
+
+func stringContains(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	sub, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Contains(s, sub), nil
+}
+
+func stringStartsWith(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	prefix, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasPrefix(s, prefix), nil
+}
+
+func stringEndsWith(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	suffix, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasSuffix(s, suffix), nil
+}
+
+func stringIndexOf(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	sub, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	i := strings.Index(s, sub)
+	if i < 0 {
+		return int64(-1), nil
+	}
+	return int64(utf8.RuneCountInString(s[:i])), nil
+}
+
+func stringRepeat(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	n, err := intArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, NewRuntimeError(paren, "Repeat count must not be negative.")
+	}
+	if n > 0 && int64(len(s)) > maxRepeatLength/n {
+		return nil, NewRuntimeError(paren, "Repeated string too long.")
+	}
+	return strings.Repeat(s, int(n)), nil
+}
+
+func stringChars(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	elements := []interface{}{}
+	for _, r := range s {
+		elements = append(elements, string(r))
+	}
+	return NewList(elements), nil
+}
+
+func stringFormat(s string, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	return formatString(paren, s, arguments[0].(*List).Elements)
+}
@@ -0,0 +1,26 @@
+package expr_test
+
+import "testing"
+
+func TestStringMethods(t *testing.T) {
+	runScripts(t, []scriptTest{
+		{source: `print "héllo".len(); print "".len();`, output: "5\n0\n"},
+		{source: `print "Héllo".upper(); print "HÉLLO".lower();`, output: "HÉLLO\nhéllo\n"},
+		{source: `print "  a b  ".trim() + "|"; print "xxaxx".trim("x");`, output: "a b|\na\n"},
+		{source: `print "a,b,,c".split(","); print "hé".split(""); print "".split(",");`, output: "[\"a\", \"b\", \"\", \"c\"]\n[\"h\", \"é\"]\n[\"\"]\n"},
+		{source: `print ", ".join(["a", 1, nil]); print "-".join([]);`, output: "a, 1, nil\n\n"},
+		{source: `print "aaa".replace("a", "b"); print "aaa".replace("a", "b", 2); print "aaa".replace("a", "b", nil);`, output: "bbb\nbba\nbbb\n"},
+		{source: `print "hello".contains("ell"); print "hello".startsWith("he"); print "hello".endsWith("lo"); print "hello".startsWith("lo");`, output: "true\ntrue\ntrue\nfalse\n"},
+		{source: `print "héllo".indexOf("l"); print "hello".indexOf("z"); print "hello".indexOf("");`, output: "2\n-1\n0\n"},
+		{source: `print "ab".repeat(3); print "ab".repeat(0) + "|"; print "".repeat(9223372036854775807) + "|";`, output: "ababab\n|\n|\n"},
+		{source: `print "hé".chars(); print "".chars();`, output: "[\"h\", \"é\"]\n[]\n"},
+		{source: `print "{} and {}".format(1, "two");`, output: "1 and two\n"},
+		{source: `var upper = "abc".upper; print upper();`, output: "ABC\n"},
+		{source: `print "ab".repeat(-1);`, err: "Repeat count must not be negative."},
+		{source: `print "ab".repeat(9223372036854775807);`, err: "Repeated string too long."},
+		{source: `print "ab".repeat(1073741824);`, err: "Repeated string too long."},
+		{source: `print "ab".repeat("3");`, err: "Argument 'n' of repeat() must be an integer."},
+		{source: `print "ab".split();`, err: "Expected 1 argument but got 0."},
+		{source: `print "ab".reverse();`, err: "Undefined string method 'reverse'."},
+	})
+}
@@ -0,0 +1,462 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"time"
+	// Time zones work without a tz database on the host
+	_ "time/tzdata"
+
+	"github.com/levi/holo/token"
+)
+
+// Time is an instant in a time zone. Scripts call methods on it, such as t.year() or t.format(layout):
+//
+//	year(), month(), day(), hour(), minute(), second(), nanosecond()
+//	weekday()               day of the week, as "Monday"
+//	unix(), unixMilli()     time since the Unix epoch in seconds or milliseconds
+//	zone()                  abbreviated time zone name, as "CET"
+//	format(layout?)         text in a Go reference layout, RFC 3339 by default
+//	inZone(name), utc()     the same instant in another time zone
+//	add(duration)           the time moved by duration
+//	sub(t)                  the duration between two times
+//	before(t), after(t)     how two instants are ordered
+//
+// Times and durations also support +, - and comparison operators, and durations * and / by numbers.
+type Time struct {
+	t time.Time
+}
+
+// Duration is an elapsed time with nanosecond precision. Its methods hours(), minutes() and
+// seconds() return floats, and milliseconds() an integer.
+type Duration time.Duration
+
+func (t Time) String() string {
+	return t.t.Format(time.RFC3339Nano)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Clock is the source of the current time for clock() and time.now(). Tests can replace it to freeze time.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the time of the host operating system
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always reads the same time
+type FixedClock struct {
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+var clockSource Clock = SystemClock{}
+
+// SetClock replaces the source of the current time
+func SetClock(c Clock) {
+	clockSource = c
+}
+
+// layouts names the layouts exported by the time module
+var layouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+	"Kitchen":     time.Kitchen,
+}
+
+func init() {
+	DefineNative(NewNative(NewSignature("clock", nil, 0, false), clock))
+
+	m := DefineNativeModule("time")
+	for name, layout := range layouts {
+		m.Export(name, layout)
+	}
+	m.Export("nanosecond", Duration(time.Nanosecond))
+	m.Export("microsecond", Duration(time.Microsecond))
+	m.Export("millisecond", Duration(time.Millisecond))
+	m.Export("second", Duration(time.Second))
+	m.Export("minute", Duration(time.Minute))
+	m.Export("hour", Duration(time.Hour))
+
+	m.exportNative("now", []string{"zone"}, 0, false, timeNow)
+	m.exportNative("parse", []string{"text", "layout", "zone"}, 1, false, timeParse)
+	m.exportNative("format", []string{"time", "layout"}, 1, false, timeFormat)
+	m.exportNative("date", []string{"year", "month", "day", "hour", "minute", "second", "zone"}, 3, false, timeDate)
+	m.exportNative("unix", []string{"seconds", "zone"}, 1, false, timeUnix)
+	m.exportNative("duration", []string{"text"}, 1, false, timeDuration)
+	m.exportNative("since", []string{"time"}, 1, false, timeSince)
+}
+
+// clock returns the seconds since the Unix epoch as a float
+func clock(paren token.Token, arguments []interface{}) (interface{}, error) {
+	return float64(clockSource.Now().UnixNano()) / float64(time.Second), nil
+}
+
+func timeArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (time.Time, error) {
+	t, ok := arguments[index].(Time)
+	if !ok {
+		return time.Time{}, argumentError(paren, signature, index, "a time")
+	}
+	return t.t, nil
+}
+
+func durationArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (time.Duration, error) {
+	d, ok := arguments[index].(Duration)
+	if !ok {
+		return 0, argumentError(paren, signature, index, "a duration")
+	}
+	return time.Duration(d), nil
+}
+
+// zoneArgument loads an optional time zone by name, defaulting to UTC
+func zoneArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (*time.Location, error) {
+	if defaulted(arguments, index) {
+		return time.UTC, nil
+	}
+	name, err := stringArgument(paren, signature, arguments, index)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, NewRuntimeError(paren, "Unknown time zone '"+name+"'.")
+	}
+	return location, nil
+}
+
+// layoutArgument reads an optional Go reference layout, defaulting to RFC 3339
+func layoutArgument(paren token.Token, signature *Signature, arguments []interface{}, index int) (string, error) {
+	if defaulted(arguments, index) {
+		return time.RFC3339, nil
+	}
+	return stringArgument(paren, signature, arguments, index)
+}
+
+func timeNow(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	if defaulted(arguments, 0) {
+		return Time{clockSource.Now()}, nil
+	}
+	location, err := zoneArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Time{clockSource.Now().In(location)}, nil
+}
+
+func timeParse(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	text, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := layoutArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	location, err := zoneArgument(paren, signature, arguments, 2)
+	if err != nil {
+		return nil, err
+	}
+	t, err := time.ParseInLocation(layout, text, location)
+	if err != nil {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+	}
+	return Time{t}, nil
+}
+
+func timeFormat(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	t, err := timeArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := layoutArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	return t.Format(layout), nil
+}
+
+func timeDate(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	fields := make([]int, 6)
+	for i := range fields {
+		if defaulted(arguments, i) {
+			continue
+		}
+		n, err := intArgument(paren, signature, arguments, i)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = int(n)
+	}
+	location, err := zoneArgument(paren, signature, arguments, 6)
+	if err != nil {
+		return nil, err
+	}
+	return Time{time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, location)}, nil
+}
+
+// timeUnix converts seconds since the Unix epoch, which may be fractional, to a time
+func timeUnix(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	seconds, err := numberArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	location, err := zoneArgument(paren, signature, arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := seconds.(int64); ok {
+		return Time{time.Unix(n, 0).In(location)}, nil
+	}
+	whole, fraction := math.Modf(toFloat(seconds))
+	if math.IsNaN(whole) || whole < math.MinInt64 || whole >= math.MaxInt64 {
+		return nil, NewRuntimeError(paren, "Time out of range.")
+	}
+	return Time{time.Unix(int64(whole), int64(fraction*float64(time.Second))).In(location)}, nil
+}
+
+// timeDuration parses durations such as "1h30m" or "250ms"
+func timeDuration(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	text, err := stringArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return nil, NewRuntimeError(paren, fmt.Sprintf("%s() failed: %s.", signature.Name, err))
+	}
+	return Duration(d), nil
+}
+
+func timeSince(paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	t, err := timeArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Duration(clockSource.Now().Sub(t)), nil
+}
+
+// timeMethod is a native bound to the time it is called on
+type timeMethod struct {
+	signature *Signature
+	function  func(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error)
+}
+
+var timeMethods = map[string]timeMethod{
+	"year":       {NewSignature("year", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Year()) })},
+	"month":      {NewSignature("month", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Month()) })},
+	"day":        {NewSignature("day", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Day()) })},
+	"hour":       {NewSignature("hour", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Hour()) })},
+	"minute":     {NewSignature("minute", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Minute()) })},
+	"second":     {NewSignature("second", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Second()) })},
+	"nanosecond": {NewSignature("nanosecond", nil, 0, false), timeField(func(t time.Time) interface{} { return int64(t.Nanosecond()) })},
+	"weekday":    {NewSignature("weekday", nil, 0, false), timeField(func(t time.Time) interface{} { return t.Weekday().String() })},
+	"unix":       {NewSignature("unix", nil, 0, false), timeField(func(t time.Time) interface{} { return t.Unix() })},
+	"unixMilli":  {NewSignature("unixMilli", nil, 0, false), timeField(func(t time.Time) interface{} { return t.UnixMilli() })},
+	"zone":       {NewSignature("zone", nil, 0, false), timeField(func(t time.Time) interface{} { name, _ := t.Zone(); return name })},
+	"utc":        {NewSignature("utc", nil, 0, false), timeField(func(t time.Time) interface{} { return Time{t.UTC()} })},
+	"format":     {NewSignature("format", []string{"layout"}, 0, false), timeMethodFormat},
+	"inZone":     {NewSignature("inZone", []string{"zone"}, 1, false), timeInZone},
+	"add":        {NewSignature("add", []string{"duration"}, 1, false), timeAdd},
+	"sub":        {NewSignature("sub", []string{"time"}, 1, false), timeSub},
+	"before":     {NewSignature("before", []string{"time"}, 1, false), timeOrder(time.Time.Before)},
+	"after":      {NewSignature("after", []string{"time"}, 1, false), timeOrder(time.Time.After)},
+}
+
+// timeProperty binds the named method to a time
+func timeProperty(t Time, name token.Token) (interface{}, error) {
+	method, ok := timeMethods[name.Lexeme]
+	if !ok {
+		return nil, NewRuntimeError(name, "Undefined time method '"+name.Lexeme+"'.")
+	}
+	return NewNative(method.signature, func(paren token.Token, arguments []interface{}) (interface{}, error) {
+		return method.function(t.t, paren, method.signature, arguments)
+	}), nil
+}
+
+// timeField adapts a function reading a field of a time to a method
+func timeField(f func(time.Time) interface{}) func(time.Time, token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		return f(t), nil
+	}
+}
+
+func timeMethodFormat(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	layout, err := layoutArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return t.Format(layout), nil
+}
+
+func timeInZone(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	location, err := zoneArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Time{t.In(location)}, nil
+}
+
+func timeAdd(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	d, err := durationArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Time{t.Add(d)}, nil
+}
+
+func timeSub(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+	other, err := timeArgument(paren, signature, arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Duration(t.Sub(other)), nil
+}
+
+func timeOrder(f func(time.Time, time.Time) bool) func(time.Time, token.Token, *Signature, []interface{}) (interface{}, error) {
+	return func(t time.Time, paren token.Token, signature *Signature, arguments []interface{}) (interface{}, error) {
+		other, err := timeArgument(paren, signature, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		return f(t, other), nil
+	}
+}
+
+// durationProperty binds the named method to a duration
+func durationProperty(d Duration, name token.Token) (interface{}, error) {
+	var value interface{}
+	switch name.Lexeme {
+	case "hours":
+		value = time.Duration(d).Hours()
+	case "minutes":
+		value = time.Duration(d).Minutes()
+	case "seconds":
+		value = time.Duration(d).Seconds()
+	case "milliseconds":
+		value = int64(time.Duration(d) / time.Millisecond)
+	default:
+		return nil, NewRuntimeError(name, "Undefined duration method '"+name.Lexeme+"'.")
+	}
+	return NewNative(NewSignature(name.Lexeme, nil, 0, false), func(paren token.Token, arguments []interface{}) (interface{}, error) {
+		return value, nil
+	}), nil
+}
+
+func isTemporal(value interface{}) bool {
+	switch value.(type) {
+	case Time, Duration:
+		return true
+	}
+	return false
+}
+
+// timeOperation applies a binary operator where either operand is a time or duration
+func timeOperation(operator token.Token, left, right interface{}) (interface{}, error) {
+	switch operator.TokenType {
+	case token.EqualEqualToken:
+		return isEqual(left, right), nil
+	case token.BangEqualToken:
+		return !isEqual(left, right), nil
+	}
+
+	switch l := left.(type) {
+	case Time:
+		switch r := right.(type) {
+		case Time:
+			switch operator.TokenType {
+			case token.MinusToken:
+				return Duration(l.t.Sub(r.t)), nil
+			case token.GreaterToken, token.GreaterEqualToken, token.LessToken, token.LessEqualToken:
+				return compareOrder(operator, l.t.Compare(r.t))
+			}
+		case Duration:
+			switch operator.TokenType {
+			case token.PlusToken:
+				return Time{l.t.Add(time.Duration(r))}, nil
+			case token.MinusToken:
+				return Time{l.t.Add(-time.Duration(r))}, nil
+			}
+		}
+	case Duration:
+		switch r := right.(type) {
+		case Time:
+			if operator.TokenType == token.PlusToken {
+				return Time{r.t.Add(time.Duration(l))}, nil
+			}
+		case Duration:
+			switch operator.TokenType {
+			case token.PlusToken:
+				sum := l + r
+				if (r > 0 && sum < l) || (r < 0 && sum > l) {
+					return nil, NewRuntimeError(operator, "Duration out of range.")
+				}
+				return sum, nil
+			case token.MinusToken:
+				difference := l - r
+				if (r > 0 && difference > l) || (r < 0 && difference < l) {
+					return nil, NewRuntimeError(operator, "Duration out of range.")
+				}
+				return difference, nil
+			case token.SlashToken:
+				if r == 0 {
+					return nil, NewRuntimeError(operator, "Division by zero.")
+				}
+				return float64(l) / float64(r), nil
+			case token.GreaterToken, token.GreaterEqualToken, token.LessToken, token.LessEqualToken:
+				return compareOrder(operator, compareNumbers(int64(l), int64(r)))
+			}
+		default:
+			if isNumber(r) {
+				switch operator.TokenType {
+				case token.StarToken:
+					return scaleDuration(operator, float64(l)*toFloat(r))
+				case token.SlashToken:
+					if toFloat(r) == 0 {
+						return nil, NewRuntimeError(operator, "Division by zero.")
+					}
+					return scaleDuration(operator, float64(l)/toFloat(r))
+				}
+			}
+		}
+	default:
+		if d, ok := right.(Duration); ok && isNumber(l) && operator.TokenType == token.StarToken {
+			return scaleDuration(operator, toFloat(l)*float64(d))
+		}
+	}
+	return nil, NewRuntimeError(operator, "Unsupported operands "+stringify(left)+" and "+stringify(right)+".")
+}
+
+// scaleDuration converts the nanoseconds of a duration multiplied or divided by a number back to a duration
+func scaleDuration(operator token.Token, nanoseconds float64) (interface{}, error) {
+	if math.IsNaN(nanoseconds) || nanoseconds < math.MinInt64 || nanoseconds >= math.MaxInt64 {
+		return nil, NewRuntimeError(operator, "Duration out of range.")
+	}
+	return Duration(nanoseconds), nil
+}
+
+// compareOrder applies a comparison operator to the result of comparing two operands
+func compareOrder(operator token.Token, c int) (interface{}, error) {
+	switch operator.TokenType {
+	case token.GreaterToken:
+		return c > 0, nil
+	case token.GreaterEqualToken:
+		return c >= 0, nil
+	case token.LessToken:
+		return c < 0, nil
+	}
+	return c <= 0, nil
+}
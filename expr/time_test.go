@@ -0,0 +1,52 @@
+package expr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/levi/holo/expr"
+)
+
+func TestTimeModule(t *testing.T) {
+	expr.SetClock(expr.FixedClock{Time: time.Date(2024, 3, 10, 12, 30, 45, 500000000, time.UTC)})
+	defer expr.SetClock(expr.SystemClock{})
+
+	runScripts(t, []scriptTest{
+		{source: "print clock() == 1710073845.5;", output: "true\n"},
+		{source: "print time.now(); print time.now(\"Asia/Tokyo\");", output: "2024-03-10T12:30:45.5Z\n2024-03-10T21:30:45.5+09:00\n"},
+		{source: "var t = time.now(); print t.year(); print t.month(); print t.day(); print t.hour(); print t.minute(); print t.second(); print t.nanosecond(); print t.weekday();", output: "2024\n3\n10\n12\n30\n45\n500000000\nSunday\n"},
+		{source: "var t = time.now(); print t.unix(); print t.unixMilli(); print t.zone(); print t.inZone(\"Europe/Paris\").zone();", output: "1710073845\n1710073845500\nUTC\nCET\n"},
+		{source: "print time.parse(\"2024-01-02T03:04:05+01:00\"); print time.parse(\"2024-01-02\", time.DateOnly, \"America/New_York\");", output: "2024-01-02T03:04:05+01:00\n2024-01-02T00:00:00-05:00\n"},
+		{source: "var t = time.date(2024, 2, 29, 13, 5); print t; print t.format(time.Kitchen); print time.format(t, \"02/01/2006\");", output: "2024-02-29T13:05:00Z\n1:05PM\n29/02/2024\n"},
+		{source: "print time.unix(0); print time.unix(86400, \"Asia/Kolkata\");", output: "1970-01-01T00:00:00Z\n1970-01-02T05:30:00+05:30\n"},
+		{source: "print time.since(time.date(2024, 3, 10, 12)); print time.duration(\"1h30m\"); print time.hour * 2 + time.minute;", output: "30m45.5s\n1h30m0s\n2h1m0s\n"},
+		{source: "var d = time.duration(\"90m\"); print d.hours(); print d.minutes(); print d.seconds(); print d.milliseconds();", output: "1.5\n90.0\n5400.0\n5400000\n"},
+		{source: "var a = time.date(2024, 1, 1); var b = a + time.hour; print b; print b - a; print b - time.minute; print time.hour + a;", output: "2024-01-01T01:00:00Z\n1h0m0s\n2024-01-01T00:59:00Z\n2024-01-01T01:00:00Z\n"},
+		{source: "var a = time.date(2024, 1, 1); print a < a + time.second; print a.before(a); print a.after(a.add(time.second * -1)); print a.sub(a + time.hour);", output: "true\nfalse\ntrue\n-1h0m0s\n"},
+		{source: "print time.hour / time.minute; print time.hour / 4; print time.second * 1.5; print 2 * time.second; print time.hour > time.minute;", output: "60.0\n15m0s\n1.5s\n2s\ntrue\n"},
+		{source: "print time.date(2024, 1, 1) == time.date(2024, 1, 1); print time.second == time.millisecond * 1000;", output: "true\ntrue\n"},
+		{source: "print time.hour / (time.hour - time.hour);", err: "Division by zero."},
+		{source: "print time.hour / 0;", err: "Division by zero."},
+		{source: "print time.hour / 0.0;", err: "Division by zero."},
+		{source: "print time.hour / 0n;", err: "Division by zero."},
+		{source: "print time.hour * math.inf;", err: "Duration out of range."},
+		{source: "print 3000000 * time.hour;", err: "Duration out of range."},
+		{source: "print time.hour * math.nan;", err: "Duration out of range."},
+		{source: "print time.hour / math.inf * 0;", output: "0s\n"},
+		{source: "var d = time.hour * 2000000; print d + d;", err: "Duration out of range."},
+		{source: "var d = time.hour * -2000000; print d + d;", err: "Duration out of range."},
+		{source: "var d = time.hour * 2000000; print d - d * -1;", err: "Duration out of range."},
+		{source: "var d = time.hour * 2000000; print d - d, d * -1 - d * -1;", output: "0s 0s\n"},
+		{source: "print time.unix(1.5), time.unix(-0.5);", output: "1970-01-01T00:00:01.5Z 1969-12-31T23:59:59.5Z\n"},
+		// Milliseconds stay exact for times beyond the range of int64 nanoseconds
+		{source: "print time.date(1600, 1, 1).unixMilli(), time.date(3000, 1, 1).unixMilli();", output: "-11676096000000 32503680000000\n"},
+		{source: "print time.unix(10000000000000000000.0);", err: "Time out of range."},
+		{source: "print time.unix(-math.inf);", err: "Time out of range."},
+		{source: "print time.unix(math.nan);", err: "Time out of range."},
+		{source: "print time.now() + 1;", err: "Unsupported operands 2024-03-10T12:30:45.5Z and 1."},
+		{source: "print time.now(\"Mars/Olympus\");", err: "Unknown time zone 'Mars/Olympus'."},
+		{source: "print time.parse(\"soon\");", err: "time.parse() failed: parsing time \"soon\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"soon\" as \"2006\"."},
+		{source: "print time.duration(\"1 hour\");", err: "time.duration() failed: time: unknown unit \" hour\" in duration \"1 hour\"."},
+		{source: "print time.now().century();", err: "Undefined time method 'century'."},
+	})
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/levi/holo/expr"
+	"github.com/levi/holo/loader"
 	"github.com/levi/holo/parser"
 	"github.com/levi/holo/scanner"
 	"github.com/levi/holo/token"
@@ -17,10 +19,12 @@ var hadError = false
 var hadRuntimeError = false
 
 func main() {
-	if len(os.Args) > 2 {
-		fmt.Println("Usage: holo [script]")
-	} else if len(os.Args) == 2 {
-		e := runFile(os.Args[0])
+	expr.SetModuleLoader(loader.NewLoader(loader.SearchPath()))
+	expr.EnableSubprocesses(subprocessesAllowed())
+
+	if len(os.Args) >= 2 {
+		expr.SetArgs(os.Args[2:])
+		e := runFile(os.Args[1])
 		if e != nil {
 			panic(e)
 		}
@@ -29,11 +33,22 @@ func main() {
 	}
 }
 
+// subprocessesAllowed reports whether the HOLO_ALLOW_RUN environment variable opts in to os.run,
+// which is off by default so running a script can't start other programs unless asked to
+func subprocessesAllowed() bool {
+	return os.Getenv("HOLO_ALLOW_RUN") == "1"
+}
+
 func runFile(path string) error {
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
+	// Imports resolve relative to the script
+	expr.MainModule().Path, err = filepath.Abs(path)
+	if err != nil {
+		return err
+	}
 	run(string(bytes))
 	if hadError {
 		return errors.New("Parsing error")
@@ -48,17 +63,18 @@ func runPrompt() {
 	scanner := bufio.NewScanner(os.Stdin)
 	for {
 		fmt.Print("> ")
-		if scanner.Scan() {
-			text := scanner.Text()
-			if text == "exit" {
-				os.Exit(0)
-			}
-			run(text)
-			hadError = false
+		if !scanner.Scan() {
+			break
 		}
-		if err := scanner.Err(); err != nil {
-			fmt.Fprintln(os.Stderr, "Error reading standard input:", err)
+		text := scanner.Text()
+		if text == "exit" {
+			os.Exit(0)
 		}
+		run(text)
+		hadError = false
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error reading standard input:", err)
 	}
 }
 
@@ -73,19 +89,30 @@ func run(source string) {
 		}
 	}
 
-	for _, token := range tokens {
-		fmt.Println(token.String())
-	}
-
 	p := parser.NewParser(tokens)
 	statements, err := p.Parse()
 
+	for _, warning := range p.Warnings {
+		reportParseWarning(warning)
+	}
+
 	if err, ok := err.(*parser.ParseError); ok {
 		reportParseError(*err)
 		return
 	}
 
+	resolveErrors := expr.Resolve(statements)
+	for _, err := range resolveErrors {
+		reportResolveError(*err)
+	}
+	if len(resolveErrors) > 0 {
+		return
+	}
+
 	err = expr.Interpret(statements)
+	if exit, ok := err.(*expr.Exit); ok {
+		os.Exit(exit.Code)
+	}
 	if err, ok := err.(*expr.RuntimeError); ok {
 		reportRuntimeError(*err)
 		return
@@ -105,12 +132,25 @@ func reportParseError(err parser.ParseError) {
 	}
 }
 
+func reportParseWarning(warning parser.ParseError) {
+	t := warning.Token
+	fmt.Fprintf(os.Stderr, "[line %d] Warning at '%s': %s\n", t.Line, t.Lexeme, warning.Message)
+}
+
+func reportResolveError(err expr.ResolveError) {
+	report(err.Token.Line, " at '"+err.Token.Lexeme+"'", err.Message)
+}
+
 func report(line int, where, message string) {
 	fmt.Fprintf(os.Stderr, "[line %d] Error %s: %s\n", line, where, message)
 	hadError = true
 }
 
 func reportRuntimeError(err expr.RuntimeError) {
-	fmt.Fprintf(os.Stderr, "%s\n[line %d]\n", err.Message, err.Token.Line)
+	fmt.Fprintln(os.Stderr, err.Message)
+	// The stack leads from the error back through each call site to the script
+	for _, line := range err.Stack {
+		fmt.Fprintln(os.Stderr, line)
+	}
 	hadRuntimeError = true
 }
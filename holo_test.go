@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/levi/holo/expr"
+	"github.com/levi/holo/parser"
+	"github.com/levi/holo/scanner"
+)
+
+func TestSubprocessesAllowed(t *testing.T) {
+	tests := []struct {
+		value   string
+		allowed bool
+	}{
+		{value: "", allowed: false},
+		{value: "0", allowed: false},
+		{value: "yes", allowed: false},
+		{value: "1", allowed: true},
+	}
+	for _, test := range tests {
+		t.Setenv("HOLO_ALLOW_RUN", test.value)
+		if allowed := subprocessesAllowed(); allowed != test.allowed {
+			t.Errorf("%q: got %v, want %v", test.value, allowed, test.allowed)
+		}
+	}
+}
+
+func TestRunWithoutOptIn(t *testing.T) {
+	t.Setenv("HOLO_ALLOW_RUN", "")
+	expr.EnableSubprocesses(subprocessesAllowed())
+
+	source := `os.run(["true"]);`
+	statements, err := parser.NewParser(scanner.NewScanner(source).ScanTokens()).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Running subprocesses is not enabled."
+	if err := expr.Interpret(statements); err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
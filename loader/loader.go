@@ -0,0 +1,127 @@
+package loader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/levi/holo/expr"
+	"github.com/levi/holo/parser"
+	"github.com/levi/holo/scanner"
+	"github.com/levi/holo/token"
+)
+
+// Loader reads imported files as modules. An import path is resolved relative to the
+// importing file first, then against each directory of the search path in order.
+type Loader struct {
+	searchPath []string
+	modules    map[string]*expr.Module
+	// loading holds the modules being imported, innermost last
+	loading []string
+}
+
+// NewLoader allocates a loader that falls back to the directories of searchPath
+func NewLoader(searchPath []string) *Loader {
+	l := new(Loader)
+	l.searchPath = searchPath
+	l.modules = make(map[string]*expr.Module)
+	return l
+}
+
+// SearchPath splits the HOLO_PATH environment variable into directories
+func SearchPath() []string {
+	return filepath.SplitList(os.Getenv("HOLO_PATH"))
+}
+
+// Load runs the module at path once and returns it from the cache afterwards
+func (l *Loader) Load(keyword token.Token, importer *expr.Module, path string) (*expr.Module, error) {
+	resolved, ok := l.resolve(importer, path)
+	if !ok {
+		return nil, expr.NewRuntimeError(keyword, "Cannot find module '"+path+"'.")
+	}
+	if module, ok := l.modules[resolved]; ok {
+		return module, nil
+	}
+
+	chain := append([]string{expr.MainModule().Path}, l.loading...)
+	for i, loading := range chain {
+		if loading == resolved {
+			cycle := append(chain[i:], resolved)
+			return nil, expr.NewRuntimeError(keyword, "Import cycle: "+strings.Join(cycle, " -> ")+".")
+		}
+	}
+
+	statements, err := l.parse(keyword, path, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	module := expr.NewModule(resolved)
+	l.loading = append(l.loading, resolved)
+	err = expr.RunModule(module, statements)
+	l.loading = l.loading[:len(l.loading)-1]
+	if err != nil {
+		return nil, err
+	}
+
+	l.modules[resolved] = module
+	return module, nil
+}
+
+// resolve finds the absolute path of an import, searching the importer's directory then the search path
+func (l *Loader) resolve(importer *expr.Module, path string) (string, bool) {
+	if filepath.IsAbs(path) {
+		return path, exists(path)
+	}
+
+	dir := "."
+	if importer.Path != "" {
+		dir = filepath.Dir(importer.Path)
+	}
+	for _, base := range append([]string{dir}, l.searchPath...) {
+		candidate, err := filepath.Abs(filepath.Join(base, path))
+		if err == nil && exists(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// parse reads a module's statements, reporting scan, parse and resolve errors at the import
+func (l *Loader) parse(keyword token.Token, path, resolved string) ([]expr.Stmt, error) {
+	bytes, err := ioutil.ReadFile(resolved)
+	if err != nil {
+		return nil, expr.NewRuntimeError(keyword, "Cannot read module '"+path+"'.")
+	}
+
+	s := scanner.NewScanner(string(bytes))
+	tokens := s.ScanTokens()
+	if len(s.Errors) > 0 {
+		e := s.Errors[0]
+		return nil, moduleError(keyword, path, e.Line, e.Error())
+	}
+
+	p := parser.NewParser(tokens)
+	statements, err := p.Parse()
+	if err, ok := err.(*parser.ParseError); ok {
+		return nil, moduleError(keyword, path, err.Token.Line, err.Message)
+	}
+
+	resolveErrors := expr.Resolve(statements)
+	if len(resolveErrors) > 0 {
+		e := resolveErrors[0]
+		return nil, moduleError(keyword, path, e.Token.Line, e.Message)
+	}
+	return statements, nil
+}
+
+func moduleError(keyword token.Token, path string, line int, message string) error {
+	return expr.NewRuntimeError(keyword, fmt.Sprintf("Error in module '%s' [line %d]: %s", path, line, message))
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,199 @@
+package loader
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/levi/holo/expr"
+	"github.com/levi/holo/parser"
+	"github.com/levi/holo/scanner"
+)
+
+// loaderTest writes files to a fresh directory, then runs source as its main.holo. The files of
+// search go in a directory on the search path, and {dir} in err stands for the main directory.
+type loaderTest struct {
+	files  map[string]string
+	search map[string]string
+	source string
+	output string
+	err    string
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// runMain writes source to main.holo in dir and runs it as the main module, importing through a new loader
+func runMain(t *testing.T, dir, source string, searchPath []string) (string, error) {
+	t.Helper()
+	s := scanner.NewScanner(source)
+	tokens := s.ScanTokens()
+	if len(s.Errors) > 0 {
+		t.Fatalf("%q: scanner error %q", source, s.Errors[0].Error())
+	}
+	statements, err := parser.NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("%q: parse error %q", source, err)
+	}
+
+	main := filepath.Join(dir, "main.holo")
+	writeFiles(t, dir, map[string]string{"main.holo": source})
+	expr.SetModuleLoader(NewLoader(searchPath))
+	expr.MainModule().Path = main
+	var output bytes.Buffer
+	expr.SetOutput(&output)
+	defer expr.SetOutput(os.Stdout)
+	err = expr.Interpret(statements)
+	return output.String(), err
+}
+
+func TestLoad(t *testing.T) {
+	tests := []loaderTest{
+		{
+			files:  map[string]string{"lib/strings.holo": `export fn shout(s) { return s + "!"; }`},
+			source: `import "lib/strings.holo" as s; print s.shout("hi");`,
+			output: "hi!\n",
+		},
+		// Imports within a module resolve relative to that module
+		{
+			files: map[string]string{
+				"lib/a.holo": `import "b.holo" as b; export var name = "a" + b.name;`,
+				"lib/b.holo": `export var name = "b";`,
+			},
+			source: `import "lib/a.holo" as a; print a.name;`,
+			output: "ab\n",
+		},
+		// Each module is run once, then shared by every importer
+		{
+			files: map[string]string{
+				"counter.holo": `print "loaded"; export var n = 1;`,
+				"other.holo":   `import "counter.holo" as c; export var n = c.n + 1;`,
+			},
+			source: `import "counter.holo" as a; import "./counter.holo" as b; import "other.holo" as o; print a == b; print o.n;`,
+			output: "loaded\ntrue\n2\n",
+		},
+		// Each module has its own globals
+		{
+			files:  map[string]string{"m.holo": `var x = "module"; export fn getX() { return x; }`},
+			source: `var x = "main"; import "m.holo" as m; print m.getX(); print x;`,
+			output: "module\nmain\n",
+		},
+		{
+			search: map[string]string{"util.holo": `export var where = "search";`},
+			source: `import "util.holo" as u; print u.where;`,
+			output: "search\n",
+		},
+		// The importing directory takes precedence over the search path
+		{
+			files:  map[string]string{"util.holo": `export var where = "local";`},
+			search: map[string]string{"util.holo": `export var where = "search";`},
+			source: `import "util.holo" as u; print u.where;`,
+			output: "local\n",
+		},
+		{
+			source: `import "nope.holo" as n;`,
+			err:    "Cannot find module 'nope.holo'.",
+		},
+		{
+			files:  map[string]string{"lib/a.holo": ""},
+			source: `import "lib" as l;`,
+			err:    "Cannot find module 'lib'.",
+		},
+		{
+			files:  map[string]string{"m.holo": `var hidden = 1; export var shown = 2;`},
+			source: `import "m.holo" as m; print m.shown; print m.hidden;`,
+			output: "2\n",
+			err:    "Module '{dir}/m.holo' does not export 'hidden'.",
+		},
+		{
+			files: map[string]string{
+				"a.holo": `import "b.holo" as b;`,
+				"b.holo": `import "a.holo" as a;`,
+			},
+			source: `import "a.holo" as a;`,
+			err:    "Import cycle: {dir}/a.holo -> {dir}/b.holo -> {dir}/a.holo.",
+		},
+		{
+			files:  map[string]string{"a.holo": `import "main.holo" as m;`},
+			source: `import "a.holo" as a;`,
+			err:    "Import cycle: {dir}/main.holo -> {dir}/a.holo -> {dir}/main.holo.",
+		},
+		{
+			files:  map[string]string{"self.holo": `import "self.holo" as s;`},
+			source: `import "self.holo" as s;`,
+			err:    "Import cycle: {dir}/self.holo -> {dir}/self.holo.",
+		},
+		{
+			files:  map[string]string{"bad.holo": "var x = 1;\nvar = 2;"},
+			source: `import "bad.holo" as b;`,
+			err:    "Error in module 'bad.holo' [line 2]: Expected variable name.",
+		},
+		{
+			files:  map[string]string{"bad.holo": `print "unterminated;`},
+			source: `import "bad.holo" as b;`,
+			err:    "Error in module 'bad.holo' [line 1]: Unterminated string",
+		},
+		{
+			files:  map[string]string{"bad.holo": "const x = 1;\nx = 2;"},
+			source: `import "bad.holo" as b;`,
+			err:    "Error in module 'bad.holo' [line 2]: Cannot assign to constant 'x'.",
+		},
+		// Runtime errors in a module stop the import
+		{
+			files:  map[string]string{"bad.holo": `print 1 / 0;`},
+			source: `import "bad.holo" as b; print "unreachable";`,
+			err:    "Division by zero.",
+		},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		search := t.TempDir()
+		writeFiles(t, dir, test.files)
+		writeFiles(t, search, test.search)
+
+		output, err := runMain(t, dir, test.source, []string{search})
+		if output != test.output {
+			t.Errorf("%q: printed %q, want %q", test.source, output, test.output)
+		}
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+		want := strings.Replace(test.err, "{dir}", dir, -1)
+		if message != want {
+			t.Errorf("%q: got error %q, want %q", test.source, message, want)
+		}
+	}
+}
+
+func TestSearchPath(t *testing.T) {
+	tests := []struct {
+		value string
+		path  []string
+	}{
+		{value: "", path: []string{}},
+		{value: "/a", path: []string{"/a"}},
+		{value: "/a" + string(os.PathListSeparator) + "b", path: []string{"/a", "b"}},
+	}
+	for _, test := range tests {
+		t.Setenv("HOLO_PATH", test.value)
+		if path := SearchPath(); !reflect.DeepEqual(path, test.path) {
+			t.Errorf("%q: got %q, want %q", test.value, path, test.path)
+		}
+	}
+}
@@ -1,14 +1,26 @@
 package parser
 
 import (
+	"math/big"
+	"strings"
+
 	"github.com/levi/holo/expr"
 	"github.com/levi/holo/token"
 )
 
 // Parser parses a flat sequence of tokens into an AST, reporting errors when encountered
 type Parser struct {
+	Warnings []ParseError
+
 	tokens  []*token.Token
 	current int
+
+	// loops holds the labels of the loops enclosing the current statement, "" for unlabeled loops
+	loops []string
+	// functions counts the function bodies enclosing the current statement
+	functions int
+	// blocks counts the blocks enclosing the current declaration
+	blocks int
 }
 
 // NewParser allocates a new parser with a sequence of tokens to parse
@@ -22,69 +34,751 @@ func NewParser(tokens []*token.Token) *Parser {
 func (p *Parser) Parse() ([]expr.Stmt, error) {
 	var statements []expr.Stmt
 	for !p.isAtEnd() {
-		statement, err := p.statement()
+		statement, err := p.declaration()
 		if err != nil {
 			return statements, err
 		}
-		statements = append(statements, statement)
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+func (p *Parser) declaration() (expr.Stmt, error) {
+	if p.match(token.ImportToken) {
+		return p.importDeclaration()
+	}
+	if p.match(token.ExportToken) {
+		return p.exportDeclaration()
+	}
+	if p.match(token.VarToken) {
+		return p.varDeclaration()
+	}
+	if p.match(token.ConstToken) {
+		return p.constDeclaration()
+	}
+	if p.match(token.FnToken) {
+		return p.function()
+	}
+	return p.statement()
+}
+
+// importDeclaration parses import "path" as name;
+func (p *Parser) importDeclaration() (expr.Stmt, error) {
+	keyword := p.previous()
+	path, err := p.consume(token.StringToken, "Expected module path after 'import'.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.AsToken, "Expected 'as' after module path.")
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.consume(token.IdentifierToken, "Expected module name after 'as'.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SemicolonToken, "Expected ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewImport(*keyword, path.Literal.(string), *name), nil
+}
+
+// exportDeclaration parses export followed by a top-level var, const or fn declaration
+func (p *Parser) exportDeclaration() (expr.Stmt, error) {
+	keyword := p.previous()
+	if p.blocks > 0 {
+		return nil, NewParseError(*keyword, "Can only export top-level declarations.")
+	}
+
+	var declaration expr.Stmt
+	var err error
+	if p.match(token.VarToken) {
+		declaration, err = p.varDeclaration()
+	} else if p.match(token.ConstToken) {
+		declaration, err = p.constDeclaration()
+	} else if p.match(token.FnToken) {
+		declaration, err = p.function()
+	} else {
+		return nil, NewParseError(*(p.peek()), "Expected declaration after 'export'.")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewExport(*keyword, declaration), nil
+}
+
+// function parses fn name(a, b = default, ...rest) { body }
+func (p *Parser) function() (expr.Stmt, error) {
+	name, err := p.consume(token.IdentifierToken, "Expected function name.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.LeftParenToken, "Expected '(' after function name.")
+	if err != nil {
+		return nil, err
+	}
+
+	params := []token.Token{}
+	defaults := []expr.Expr{}
+	variadic := false
+	for !p.check(token.RightParenToken) {
+		if p.match(token.DotDotDotToken) {
+			param, err := p.consume(token.IdentifierToken, "Expected parameter name after '...'.")
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, *param)
+			defaults = append(defaults, nil)
+			variadic = true
+			break
+		}
+
+		param, err := p.consume(token.IdentifierToken, "Expected parameter name.")
+		if err != nil {
+			return nil, err
+		}
+		var defaultValue expr.Expr
+		if p.match(token.EqualToken) {
+			defaultValue, err = p.expression()
+			if err != nil {
+				return nil, err
+			}
+		} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+			return nil, NewParseError(*param, "Parameter without a default can't follow parameters with defaults.")
+		}
+		params = append(params, *param)
+		defaults = append(defaults, defaultValue)
+
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+	_, err = p.consume(token.RightParenToken, "Expected ')' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+
+	// Loops outside the function can't be targeted by break or continue inside it
+	loops := p.loops
+	p.loops = nil
+	p.functions++
+	body, err := p.blockBody("function signature")
+	p.functions--
+	p.loops = loops
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewFn(*name, params, defaults, variadic, body), nil
+}
+
+func (p *Parser) varDeclaration() (expr.Stmt, error) {
+	if p.check(token.LeftBracketToken) || p.check(token.LeftBraceToken) {
+		return p.destructuringDeclaration()
+	}
+
+	name, err := p.consume(token.IdentifierToken, "Expected variable name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var initializer expr.Expr
+	if p.match(token.EqualToken) {
+		initializer, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(token.SemicolonToken, "Expected ';' after variable declaration.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewVar(*name, initializer), nil
+}
+
+func (p *Parser) constDeclaration() (expr.Stmt, error) {
+	name, err := p.consume(token.IdentifierToken, "Expected constant name.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.EqualToken, "Expected '=' after constant name.")
+	if err != nil {
+		return nil, err
+	}
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SemicolonToken, "Expected ';' after constant declaration.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewConst(*name, initializer), nil
+}
+
+// destructuringDeclaration parses var [a, ...rest] = list; or var {key, other: name} = map;
+func (p *Parser) destructuringDeclaration() (expr.Stmt, error) {
+	keyword := p.previous()
+	pattern, err := p.declarationPattern()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.EqualToken, "Expected '=' after destructuring pattern.")
+	if err != nil {
+		return nil, err
+	}
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SemicolonToken, "Expected ';' after variable declaration.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewVarPattern(*keyword, pattern, initializer), nil
+}
+
+func (p *Parser) statement() (expr.Expr, error) {
+	if p.match(token.PrintToken, token.EprintToken) {
+		return p.printStatement()
+	}
+	if p.match(token.ForToken) {
+		return p.forStatement("")
+	}
+	if p.match(token.WhileToken) {
+		return p.whileStatement("")
+	}
+	if p.match(token.IfToken) {
+		return p.ifStatement()
+	}
+	if p.match(token.BreakToken, token.ContinueToken) {
+		return p.loopControlStatement()
+	}
+	if p.match(token.ThrowToken) {
+		return p.throwStatement()
+	}
+	if p.match(token.ReturnToken) {
+		return p.returnStatement()
+	}
+	if p.match(token.TryToken) {
+		return p.tryStatement()
+	}
+	if p.check(token.IdentifierToken) && p.checkNext(token.ColonToken) {
+		return p.labeledStatement()
+	}
+	if p.check(token.LeftBraceToken) && !p.startsMapLiteral() {
+		p.advance()
+		statements, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return expr.NewBlock(statements), nil
+	}
+	return p.expressionStatement()
+}
+
+// startsMapLiteral disambiguates a '{' in statement position, which is a map literal
+// when followed by a key and ':' and otherwise a block. A block may start with a labeled loop.
+func (p *Parser) startsMapLiteral() bool {
+	if p.current+3 >= len(p.tokens) {
+		return false
+	}
+	switch p.tokens[p.current+1].TokenType {
+	case token.IdentifierToken:
+		switch p.tokens[p.current+3].TokenType {
+		case token.WhileToken, token.ForToken:
+			return false
+		}
+		fallthrough
+	case token.StringToken, token.NumberToken, token.TrueToken, token.FalseToken, token.NilToken:
+		return p.tokens[p.current+2].TokenType == token.ColonToken
+	}
+	return false
+}
+
+func (p *Parser) block() ([]expr.Stmt, error) {
+	statements := []expr.Stmt{}
+	p.blocks++
+	for !p.check(token.RightBraceToken) && !p.isAtEnd() {
+		statement, err := p.declaration()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	p.blocks--
+
+	_, err := p.consume(token.RightBraceToken, "Expected '}' after block.")
+	if err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// labeledStatement parses a loop preceded by a label such as outer:
+func (p *Parser) labeledStatement() (expr.Stmt, error) {
+	label := p.advance()
+	p.advance() // consume the :
+
+	if p.match(token.WhileToken) {
+		return p.whileStatement(label.Lexeme)
+	}
+	if p.match(token.ForToken) {
+		return p.forStatement(label.Lexeme)
+	}
+	return nil, NewParseError(*(p.peek()), "Expected loop after label.")
+}
+
+func (p *Parser) whileStatement(label string) (expr.Stmt, error) {
+	_, err := p.consume(token.LeftParenToken, "Expected '(' after 'while'.")
+	if err != nil {
+		return nil, err
+	}
+	condition, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RightParenToken, "Expected ')' after condition.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.loopBody(label)
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewWhile(condition, body, label), nil
+}
+
+// loopBody parses the body of a loop, tracking the loop so break and continue can be checked
+func (p *Parser) loopBody(label string) (expr.Stmt, error) {
+	for _, enclosing := range p.loops {
+		if label != "" && enclosing == label {
+			return nil, NewParseError(*(p.previous()), "Duplicate loop label '"+label+"'.")
+		}
+	}
+
+	p.loops = append(p.loops, label)
+	body, err := p.statement()
+	p.loops = p.loops[:len(p.loops)-1]
+	return body, err
+}
+
+// loopControlStatement parses break or continue, with an optional label of an enclosing loop
+func (p *Parser) loopControlStatement() (expr.Stmt, error) {
+	keyword := p.previous()
+	if len(p.loops) == 0 {
+		return nil, NewParseError(*keyword, "Can't use '"+keyword.Lexeme+"' outside of a loop.")
+	}
+
+	label := ""
+	if p.match(token.IdentifierToken) {
+		label = p.previous().Lexeme
+		found := false
+		for _, enclosing := range p.loops {
+			found = found || enclosing == label
+		}
+		if !found {
+			return nil, NewParseError(*(p.previous()), "Undefined loop label '"+label+"'.")
+		}
+	}
+
+	_, err := p.consume(token.SemicolonToken, "Expected ';' after '"+keyword.Lexeme+"'.")
+	if err != nil {
+		return nil, err
+	}
+	if keyword.TokenType == token.BreakToken {
+		return expr.NewBreak(*keyword, label), nil
+	}
+	return expr.NewContinue(*keyword, label), nil
+}
+
+func (p *Parser) returnStatement() (expr.Stmt, error) {
+	keyword := p.previous()
+	if p.functions == 0 {
+		return nil, NewParseError(*keyword, "Can't return from top-level code.")
+	}
+
+	var value expr.Expr
+	var err error
+	if !p.check(token.SemicolonToken) {
+		value, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.consume(token.SemicolonToken, "Expected ';' after return value.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewReturn(*keyword, value), nil
+}
+
+func (p *Parser) throwStatement() (expr.Stmt, error) {
+	keyword := p.previous()
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SemicolonToken, "Expected ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewThrow(*keyword, value), nil
+}
+
+// tryStatement parses try { } catch (e) { } finally { }, where at least one of catch or finally is required
+func (p *Parser) tryStatement() (expr.Stmt, error) {
+	keyword := p.previous()
+	body, err := p.blockBody("try")
+	if err != nil {
+		return nil, err
+	}
+
+	var name token.Token
+	var catchBody, finallyBody []expr.Stmt
+	if p.match(token.CatchToken) {
+		_, err = p.consume(token.LeftParenToken, "Expected '(' after 'catch'.")
+		if err != nil {
+			return nil, err
+		}
+		n, err := p.consume(token.IdentifierToken, "Expected error variable name.")
+		if err != nil {
+			return nil, err
+		}
+		name = *n
+		_, err = p.consume(token.RightParenToken, "Expected ')' after error variable name.")
+		if err != nil {
+			return nil, err
+		}
+		catchBody, err = p.blockBody("catch")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.match(token.FinallyToken) {
+		finallyBody, err = p.blockBody("finally")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if catchBody == nil && finallyBody == nil {
+		return nil, NewParseError(*keyword, "Expected 'catch' or 'finally' after try block.")
+	}
+	return expr.NewTry(body, name, catchBody, finallyBody), nil
+}
+
+// blockBody parses a mandatory { } block following a keyword
+func (p *Parser) blockBody(keyword string) ([]expr.Stmt, error) {
+	_, err := p.consume(token.LeftBraceToken, "Expected '{' after '"+keyword+"'.")
+	if err != nil {
+		return nil, err
+	}
+	return p.block()
+}
+
+func (p *Parser) ifStatement() (expr.Stmt, error) {
+	_, err := p.consume(token.LeftParenToken, "Expected '(' after 'if'.")
+	if err != nil {
+		return nil, err
+	}
+	condition, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RightParenToken, "Expected ')' after if condition.")
+	if err != nil {
+		return nil, err
+	}
+
+	thenBranch, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	var elseBranch expr.Stmt
+	if p.match(token.ElseToken) {
+		elseBranch, err = p.statement()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expr.NewIf(condition, thenBranch, elseBranch), nil
+}
+
+// forStatement parses for (x in iterable) or for (key, value in map)
+func (p *Parser) forStatement(label string) (expr.Stmt, error) {
+	_, err := p.consume(token.LeftParenToken, "Expected '(' after 'for'.")
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := p.consume(token.IdentifierToken, "Expected loop variable name.")
+	if err != nil {
+		return nil, err
+	}
+	names := []token.Token{*name}
+	if p.match(token.CommaToken) {
+		name, err = p.consume(token.IdentifierToken, "Expected loop variable name after ','.")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, *name)
+	}
+
+	keyword, err := p.consume(token.InToken, "Expected 'in' after loop variable.")
+	if err != nil {
+		return nil, err
+	}
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RightParenToken, "Expected ')' after for clause.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.loopBody(label)
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewForIn(names, *keyword, iterable, body, label), nil
+}
+
+func (p *Parser) expressionStatement() (expr.Stmt, error) {
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	p.consume(token.SemicolonToken, "Expected ';' after value.")
+	return expr.NewExpression(value), nil
+}
+
+// printStatement parses print or eprint with comma-separated values followed by optional sep: and end: options
+func (p *Parser) printStatement() (expr.Stmt, error) {
+	keyword := p.previous()
+	expressions := []expr.Expr{}
+	var sep, end expr.Expr
+	for !p.check(token.SemicolonToken) && !p.isAtEnd() {
+		if p.check(token.IdentifierToken) && p.checkNext(token.ColonToken) {
+			option := p.advance()
+			p.advance() // consume the :
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if option.Lexeme == "sep" && sep == nil {
+				sep = value
+			} else if option.Lexeme == "end" && end == nil {
+				end = value
+			} else {
+				return nil, NewParseError(*option, "Unknown or repeated print option '"+option.Lexeme+"'.")
+			}
+		} else {
+			if sep != nil || end != nil {
+				return nil, NewParseError(*(p.peek()), "Values can't follow print options.")
+			}
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			expressions = append(expressions, value)
+		}
+
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+	_, err := p.consume(token.SemicolonToken, "Expected ';' after value.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewPrint(*keyword, expressions, sep, end), nil
+}
+
+func (p *Parser) expression() (expr.Expr, error) {
+	return p.assignment()
+}
+
+// assignment parses a right-associative plain or compound assignment
+func (p *Parser) assignment() (expr.Expr, error) {
+	e, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.EqualToken, token.PlusEqualToken, token.MinusEqualToken, token.StarEqualToken, token.SlashEqualToken, token.PercentEqualToken) {
+		operator := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		if assignment, ok := expr.AssignTo(e, *operator, value); ok {
+			return assignment, nil
+		}
+		return nil, NewParseError(*operator, "Invalid assignment target.")
+	}
+	return e, nil
+}
+
+// conditional parses a right-associative cond ? a : b, only evaluating the chosen branch
+func (p *Parser) conditional() (expr.Expr, error) {
+	e, err := p.coalesce()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.QuestionToken) {
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.ColonToken, "Expected ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+		elseBranch, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewConditional(e, thenBranch, elseBranch)
+	}
+	return e, nil
+}
+
+// coalesce parses a ?? b, which only evaluates b when a is nil
+func (p *Parser) coalesce() (expr.Expr, error) {
+	e, err := p.equality()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.QuestionQuestionToken) {
+		operator := p.previous()
+		right, err := p.equality()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewLogical(e, *operator, right)
+	}
+	return e, nil
+}
+
+func (p *Parser) equality() (expr.Expr, error) {
+	e, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.BangEqualToken, token.EqualEqualToken) {
+		operator := p.previous()
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewBinary(e, *operator, right)
+	}
+
+	return e, nil
+}
+
+func (p *Parser) comparison() (expr.Expr, error) {
+	e, err := p.rangeLiteral()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.GreaterToken, token.GreaterEqualToken, token.LessToken, token.LessEqualToken, token.InToken) {
+		operator := p.previous()
+		right, err := p.rangeLiteral()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewBinary(e, *operator, right)
 	}
-	return statements, nil
+	return e, nil
 }
 
-func (p *Parser) statement() (expr.Expr, error) {
-	if p.match(token.PrintToken) {
-		return p.printStatement()
+// rangeLiteral parses a non-associative start..end
+func (p *Parser) rangeLiteral() (expr.Expr, error) {
+	e, err := p.bitOr()
+	if err != nil {
+		return nil, err
 	}
-	return p.expressionStatement()
+
+	if p.match(token.DotDotToken) {
+		operator := p.previous()
+		end, err := p.bitOr()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewRangeLiteral(e, *operator, end)
+	}
+	return e, nil
 }
 
-func (p *Parser) expressionStatement() (expr.Stmt, error) {
-	value, err := p.expression()
+func (p *Parser) bitOr() (expr.Expr, error) {
+	e, err := p.bitXor()
 	if err != nil {
 		return nil, err
 	}
-	p.consume(token.SemicolonToken, "Expected ';' after value.")
-	return expr.NewExpression(value), nil
+
+	for p.match(token.PipeToken) {
+		operator := p.previous()
+		right, err := p.bitXor()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewBinary(e, *operator, right)
+	}
+	return e, nil
 }
 
-func (p *Parser) printStatement() (expr.Stmt, error) {
-	value, err := p.expression()
+func (p *Parser) bitXor() (expr.Expr, error) {
+	e, err := p.bitAnd()
 	if err != nil {
 		return nil, err
 	}
-	p.consume(token.SemicolonToken, "Expected ';' after value.")
-	return expr.NewPrint(value), nil
-}
 
-func (p *Parser) expression() (expr.Expr, error) {
-	return p.equality()
+	for p.match(token.CaretToken) {
+		operator := p.previous()
+		right, err := p.bitAnd()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewBinary(e, *operator, right)
+	}
+	return e, nil
 }
 
-func (p *Parser) equality() (expr.Expr, error) {
-	e, err := p.comparison()
+func (p *Parser) bitAnd() (expr.Expr, error) {
+	e, err := p.shift()
 	if err != nil {
 		return nil, err
 	}
 
-	for p.match(token.BangEqualToken, token.EqualEqualToken) {
+	for p.match(token.AmpersandToken) {
 		operator := p.previous()
-		right, err := p.comparison()
+		right, err := p.shift()
 		if err != nil {
 			return nil, err
 		}
 		e = expr.NewBinary(e, *operator, right)
 	}
-
 	return e, nil
 }
 
-func (p *Parser) comparison() (expr.Expr, error) {
+func (p *Parser) shift() (expr.Expr, error) {
 	e, err := p.addition()
 	if err != nil {
 		return nil, err
 	}
 
-	for p.match(token.GreaterToken, token.GreaterEqualToken, token.LessToken, token.LessEqualToken) {
+	for p.match(token.LessLessToken, token.GreaterGreaterToken) {
 		operator := p.previous()
 		right, err := p.addition()
 		if err != nil {
@@ -117,7 +811,7 @@ func (p *Parser) multiplication() (expr.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
-	for p.match(token.SlashToken, token.StarToken) {
+	for p.match(token.SlashToken, token.StarToken, token.SlashSlashToken, token.PercentToken) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -129,7 +823,19 @@ func (p *Parser) multiplication() (expr.Expr, error) {
 }
 
 func (p *Parser) unary() (expr.Expr, error) {
-	if p.match(token.BangToken, token.MinusToken) {
+	if p.match(token.PlusPlusToken, token.MinusMinusToken) {
+		operator := p.previous()
+		target, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if !expr.IsAssignable(target) {
+			return nil, NewParseError(*operator, "Invalid assignment target.")
+		}
+		return expr.NewIncrement(target, *operator, true), nil
+	}
+
+	if p.match(token.BangToken, token.MinusToken, token.TildeToken) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -138,7 +844,138 @@ func (p *Parser) unary() (expr.Expr, error) {
 		return expr.NewUnary(*operator, right), nil
 	}
 
-	return p.primary()
+	return p.exponent()
+}
+
+// exponent binds tighter than unary operators on its left and is right-associative
+func (p *Parser) exponent() (expr.Expr, error) {
+	e, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.StarStarToken) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		e = expr.NewBinary(e, *operator, right)
+	}
+	return e, nil
+}
+
+func (p *Parser) postfix() (expr.Expr, error) {
+	e, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.match(token.LeftParenToken) {
+			e, err = p.finishCall(e)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(token.LeftBracketToken) {
+			e, err = p.finishIndex(e)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(token.DotToken) {
+			var name *token.Token
+			if token.IsKeyword(p.peek().TokenType) {
+				// Keywords may name properties, as in re.match
+				name = p.advance()
+			} else {
+				name, err = p.consume(token.IdentifierToken, "Expected property name after '.'.")
+				if err != nil {
+					return nil, err
+				}
+			}
+			e = expr.NewGet(e, *name)
+		} else {
+			break
+		}
+	}
+
+	if p.match(token.PlusPlusToken, token.MinusMinusToken) {
+		operator := p.previous()
+		if !expr.IsAssignable(e) {
+			return nil, NewParseError(*operator, "Invalid assignment target.")
+		}
+		e = expr.NewIncrement(e, *operator, false)
+	}
+	return e, nil
+}
+
+// finishCall parses the arguments of a call, where named arguments such as name: value follow any positional arguments
+func (p *Parser) finishCall(callee expr.Expr) (expr.Expr, error) {
+	paren := p.previous()
+	arguments := []expr.Expr{}
+	names := []string{}
+	for !p.check(token.RightParenToken) {
+		name := ""
+		if p.check(token.IdentifierToken) && p.checkNext(token.ColonToken) {
+			name = p.advance().Lexeme
+			p.advance() // consume the :
+		} else if len(names) > 0 && names[len(names)-1] != "" {
+			return nil, NewParseError(*(p.peek()), "Positional arguments can't follow named arguments.")
+		}
+
+		argument, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+		names = append(names, name)
+
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.RightParenToken, "Expected ')' after arguments.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewCall(callee, *paren, arguments, names), nil
+}
+
+// finishIndex parses the remainder of an index xs[i] or slice xs[a:b], where either slice bound may be omitted
+func (p *Parser) finishIndex(object expr.Expr) (expr.Expr, error) {
+	bracket := p.previous()
+
+	var start expr.Expr
+	var err error
+	if !p.check(token.ColonToken) {
+		start, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.match(token.ColonToken) {
+		_, err = p.consume(token.RightBracketToken, "Expected ']' after index.")
+		if err != nil {
+			return nil, err
+		}
+		return expr.NewIndex(object, *bracket, start), nil
+	}
+
+	var end expr.Expr
+	if !p.check(token.RightBracketToken) {
+		end, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(token.RightBracketToken, "Expected ']' after slice.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewSlice(object, *bracket, start, end), nil
 }
 
 func (p *Parser) primary() (expr.Expr, error) {
@@ -149,7 +986,15 @@ func (p *Parser) primary() (expr.Expr, error) {
 	} else if p.match(token.NilToken) {
 		return expr.NewLiteral(nil), nil
 	} else if p.match(token.NumberToken, token.StringToken) {
-		return expr.NewLiteral(p.previous().Literal), nil
+		return expr.NewLiteral(literalValue(p.previous())), nil
+	} else if p.match(token.IdentifierToken) {
+		return expr.NewVariable(*(p.previous())), nil
+	} else if p.match(token.LeftBracketToken) {
+		return p.listLiteral()
+	} else if p.match(token.LeftBraceToken) {
+		return p.mapLiteral()
+	} else if p.match(token.MatchToken) {
+		return p.matchExpression()
 	} else if p.match(token.LeftParenToken) {
 		e, err := p.expression()
 		if err != nil {
@@ -167,6 +1012,344 @@ func (p *Parser) primary() (expr.Expr, error) {
 	return nil, NewParseError(*(p.peek()), "Expected expression.")
 }
 
+// literalValue is the value of a number or string token. Decimal literals keep the number of
+// fractional digits they were written with, so 1.50d prints as 1.50.
+func literalValue(t *token.Token) interface{} {
+	r, ok := t.Literal.(*big.Rat)
+	if !ok {
+		return t.Literal
+	}
+	scale := 0
+	if dot := strings.Index(t.Lexeme, "."); dot >= 0 {
+		// The digits between the '.' and the 'd' suffix
+		scale = len(t.Lexeme) - dot - 2
+	}
+	return expr.NewDecimal(r, scale)
+}
+
+func (p *Parser) listLiteral() (expr.Expr, error) {
+	bracket := p.previous()
+	elements := []expr.Expr{}
+	for !p.check(token.RightBracketToken) {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.RightBracketToken, "Expected ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewListLiteral(*bracket, elements), nil
+}
+
+// mapLiteral parses {key: value, ...}, where a bare identifier key is shorthand for its name as a string
+func (p *Parser) mapLiteral() (expr.Expr, error) {
+	brace := p.previous()
+	keys := []expr.Expr{}
+	values := []expr.Expr{}
+	for !p.check(token.RightBraceToken) {
+		var key expr.Expr
+		var err error
+		if p.check(token.IdentifierToken) && p.checkNext(token.ColonToken) {
+			key = expr.NewLiteral(p.advance().Lexeme)
+		} else {
+			key, err = p.expression()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = p.consume(token.ColonToken, "Expected ':' after map key.")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.RightBraceToken, "Expected '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewMapLiteral(*brace, keys, values), nil
+}
+
+// matchExpression parses match (value) { pattern [if guard] => result, ... }
+func (p *Parser) matchExpression() (expr.Expr, error) {
+	keyword := p.previous()
+	_, err := p.consume(token.LeftParenToken, "Expected '(' after 'match'.")
+	if err != nil {
+		return nil, err
+	}
+	subject, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RightParenToken, "Expected ')' after match value.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.LeftBraceToken, "Expected '{' before match arms.")
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := []expr.Pattern{}
+	guards := []expr.Expr{}
+	bodies := []expr.Expr{}
+	coverage := make(map[string]bool)
+	for !p.check(token.RightBraceToken) {
+		armCoverage := make(map[string]bool)
+		pattern, err := p.alternativePattern(armCoverage)
+		if err != nil {
+			return nil, err
+		}
+
+		var guard expr.Expr
+		if p.match(token.IfToken) {
+			guard, err = p.expression()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Guarded arms may not match, so they never count towards exhaustiveness
+			for covered := range armCoverage {
+				coverage[covered] = true
+			}
+		}
+
+		_, err = p.consume(token.FatArrowToken, "Expected '=>' after match pattern.")
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, pattern)
+		guards = append(guards, guard)
+		bodies = append(bodies, body)
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+
+	_, err = p.consume(token.RightBraceToken, "Expected '}' after match arms.")
+	if err != nil {
+		return nil, err
+	}
+
+	p.checkExhaustive(*keyword, coverage)
+	return expr.NewMatch(*keyword, subject, patterns, guards, bodies), nil
+}
+
+// checkExhaustive warns when a match over bool literals has no catch-all arm and doesn't cover
+// each of true, false and nil, since a bool subject may also be nil
+func (p *Parser) checkExhaustive(keyword token.Token, coverage map[string]bool) {
+	if coverage["any"] || !(coverage["true"] || coverage["false"]) {
+		return
+	}
+
+	missing := []string{}
+	for _, literal := range []string{"true", "false", "nil"} {
+		if !coverage[literal] {
+			missing = append(missing, literal)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	list := missing[len(missing)-1]
+	if len(missing) > 1 {
+		list = strings.Join(missing[:len(missing)-1], ", ") + " and " + list
+	}
+	p.Warnings = append(p.Warnings, *NewParseError(keyword, "Match is not exhaustive, missing "+list+"."))
+}
+
+// alternativePattern parses one or more patterns separated by '|'. Coverage records which
+// bool and nil literals the patterns match, and "any" for the wildcard and binding patterns
+// which match every value.
+func (p *Parser) alternativePattern(coverage map[string]bool) (expr.Pattern, error) {
+	pattern, err := p.pattern(coverage)
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(token.PipeToken) {
+		return pattern, nil
+	}
+
+	alternatives := []expr.Pattern{pattern}
+	for p.match(token.PipeToken) {
+		pattern, err = p.pattern(coverage)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, pattern)
+	}
+	return expr.NewAlternativePattern(alternatives), nil
+}
+
+// pattern parses a literal, wildcard, binding, list or map pattern. Coverage is nil for nested patterns.
+func (p *Parser) pattern(coverage map[string]bool) (expr.Pattern, error) {
+	record := func(covered string) {
+		if coverage != nil {
+			coverage[covered] = true
+		}
+	}
+
+	if p.match(token.TrueToken, token.FalseToken, token.NilToken) {
+		keyword := p.previous()
+		record(keyword.Lexeme)
+		var value interface{}
+		if keyword.TokenType != token.NilToken {
+			value = keyword.TokenType == token.TrueToken
+		}
+		return expr.NewLiteralPattern(expr.NewLiteral(value)), nil
+	}
+	if p.match(token.NumberToken, token.StringToken) {
+		return expr.NewLiteralPattern(expr.NewLiteral(literalValue(p.previous()))), nil
+	}
+	if p.match(token.MinusToken) {
+		operator := p.previous()
+		number, err := p.consume(token.NumberToken, "Expected number after '-' in pattern.")
+		if err != nil {
+			return nil, err
+		}
+		return expr.NewLiteralPattern(expr.NewUnary(*operator, expr.NewLiteral(literalValue(number)))), nil
+	}
+	if p.match(token.IdentifierToken) {
+		record("any")
+		if p.previous().Lexeme == "_" {
+			return expr.NewWildcardPattern(*(p.previous())), nil
+		}
+		return expr.NewBindingPattern(*(p.previous())), nil
+	}
+	if p.match(token.LeftBracketToken) {
+		return p.listPattern(false)
+	}
+	if p.match(token.LeftBraceToken) {
+		return p.mapPattern(false)
+	}
+	return nil, NewParseError(*(p.peek()), "Expected pattern.")
+}
+
+// declarationPattern parses the subset of patterns which can destructure a declaration:
+// variable names, '_' and list or map patterns of them
+func (p *Parser) declarationPattern() (expr.Pattern, error) {
+	if p.match(token.IdentifierToken) {
+		if p.previous().Lexeme == "_" {
+			return expr.NewWildcardPattern(*(p.previous())), nil
+		}
+		return expr.NewBindingPattern(*(p.previous())), nil
+	}
+	if p.match(token.LeftBracketToken) {
+		return p.listPattern(true)
+	}
+	if p.match(token.LeftBraceToken) {
+		return p.mapPattern(true)
+	}
+	return nil, NewParseError(*(p.peek()), "Expected variable name or destructuring pattern.")
+}
+
+// subPattern parses a pattern nested within a list or map pattern
+func (p *Parser) subPattern(declaration bool) (expr.Pattern, error) {
+	if declaration {
+		return p.declarationPattern()
+	}
+	return p.alternativePattern(nil)
+}
+
+// listPattern parses [a, b, ...rest], where the rest element must come last
+func (p *Parser) listPattern(declaration bool) (expr.Pattern, error) {
+	bracket := p.previous()
+	elements := []expr.Pattern{}
+	var rest expr.Pattern
+	for !p.check(token.RightBracketToken) {
+		if p.match(token.DotDotDotToken) {
+			name, err := p.consume(token.IdentifierToken, "Expected variable name after '...'.")
+			if err != nil {
+				return nil, err
+			}
+			rest = expr.NewBindingPattern(*name)
+			break
+		}
+
+		element, err := p.subPattern(declaration)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+	_, err := p.consume(token.RightBracketToken, "Expected ']' after list pattern.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewListPattern(*bracket, elements, rest), nil
+}
+
+// mapPattern parses {key: pattern, ...}, where a bare name is shorthand for binding the key of that name
+func (p *Parser) mapPattern(declaration bool) (expr.Pattern, error) {
+	brace := p.previous()
+	keys := []interface{}{}
+	values := []expr.Pattern{}
+	for !p.check(token.RightBraceToken) {
+		var key interface{}
+		var value expr.Pattern
+		var err error
+		if p.match(token.IdentifierToken) {
+			key = p.previous().Lexeme
+			if !p.check(token.ColonToken) {
+				value = expr.NewBindingPattern(*(p.previous()))
+			}
+		} else if p.match(token.StringToken, token.NumberToken) {
+			key = literalValue(p.previous())
+		} else {
+			return nil, NewParseError(*(p.peek()), "Expected key in map pattern.")
+		}
+
+		if value == nil {
+			_, err = p.consume(token.ColonToken, "Expected ':' after map pattern key.")
+			if err != nil {
+				return nil, err
+			}
+			value, err = p.subPattern(declaration)
+			if err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+		if !p.match(token.CommaToken) {
+			break
+		}
+	}
+	_, err := p.consume(token.RightBraceToken, "Expected '}' after map pattern.")
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewMapPattern(*brace, keys, values), nil
+}
+
 func (p *Parser) match(types ...string) bool {
 	for _, t := range types {
 		if p.check(t) {
@@ -192,6 +1375,14 @@ func (p *Parser) check(t string) bool {
 	return p.peek().TokenType == t
 }
 
+// checkNext looks one token beyond the current token
+func (p *Parser) checkNext(t string) bool {
+	if p.current+1 >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.current+1].TokenType == t
+}
+
 func (p *Parser) advance() *token.Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -226,6 +1417,7 @@ func (p *Parser) sync() {
 		case token.IfToken:
 		case token.WhileToken:
 		case token.PrintToken:
+		case token.EprintToken:
 		case token.ReturnToken:
 			return
 		}
@@ -0,0 +1,308 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/levi/holo/expr"
+	"github.com/levi/holo/scanner"
+)
+
+// parserFor scans source, failing the test on scanner errors
+func parserFor(t *testing.T, source string) *Parser {
+	t.Helper()
+	s := scanner.NewScanner(source)
+	tokens := s.ScanTokens()
+	if len(s.Errors) > 0 {
+		t.Fatalf("%q: scanner error %q", source, s.Errors[0].Error())
+	}
+	return NewParser(tokens)
+}
+
+// expressionTest expects source to parse as an expression printing as tree, or to fail with err
+type expressionTest struct {
+	source string
+	tree   string
+	err    string
+}
+
+func parseExpressions(t *testing.T, tests []expressionTest) {
+	t.Helper()
+	for _, test := range tests {
+		e, err := parserFor(t, test.source).expression()
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: got error %v, want %q", test.source, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %q", test.source, err)
+			continue
+		}
+		if tree := e.(expr.AstPrinter).ToString(); tree != test.tree {
+			t.Errorf("%q: got %s, want %s", test.source, tree, test.tree)
+		}
+	}
+}
+
+// statementTest expects source to parse as statements, or to fail with err
+type statementTest struct {
+	source string
+	err    string
+}
+
+func parseStatements(t *testing.T, tests []statementTest) {
+	t.Helper()
+	for _, test := range tests {
+		_, err := parserFor(t, test.source).Parse()
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+		if message != test.err {
+			t.Errorf("%q: got error %q, want %q", test.source, message, test.err)
+		}
+	}
+}
+
+func TestArithmeticPrecedence(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: "1 + 2 * 3", tree: "(+ 1 (* 2 3))"},
+		{source: "1 - 2 - 3", tree: "(- (- 1 2) 3)"},
+		{source: "7 // 2 % 3", tree: "(% (// 7 2) 3)"},
+		{source: "2 ** 3 ** 2", tree: "(** 2 (** 3 2))"},
+		{source: "-2 ** 2", tree: "(- (** 2 2))"},
+		{source: "2 * 3 ** 2", tree: "(* 2 (** 3 2))"},
+		{source: "1 | 2 ^ 3 & 4", tree: "(| 1 (^ 2 (& 3 4)))"},
+		{source: "1 << 2 + 3", tree: "(<< 1 (+ 2 3))"},
+		{source: "1 & 2 == 2", tree: "(== (& 1 2) 2)"},
+		{source: "~1 + 2", tree: "(+ (~ 1) 2)"},
+		{source: "1 < 2 == true", tree: "(== (< 1 2) true)"},
+	})
+}
+
+func TestConditionalPrecedence(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: "true ? 1 : 2", tree: "(?: true 1 2)"},
+		{source: "true ? 1 : false ? 3 : 4", tree: "(?: true 1 (?: false 3 4))"},
+		{source: "1 == 1 ? 2 + 1 : 3", tree: "(?: (== 1 1) (+ 2 1) 3)"},
+		{source: "nil ?? 1 ?? 2", tree: "(?? (?? null 1) 2)"},
+		{source: "nil ?? 1 == 2", tree: "(?? null (== 1 2))"},
+		{source: "nil ?? true ? 1 : 2", tree: "(?: (?? null true) 1 2)"},
+		{source: "true ? 1", err: "Expected ':' after then branch of conditional expression."},
+	})
+}
+
+func TestAssignmentTargets(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: "a = b = 1", tree: "(= a (= b 1))"},
+		{source: "a += 1 + 2", tree: "(+= a (+ 1 2))"},
+		{source: "xs[0] *= 2", tree: "(*= index xs 0 2)"},
+		{source: "a++", tree: "(postfix ++ a)"},
+		{source: "--xs[1]", tree: "(prefix -- (index xs 1))"},
+		{source: "1 = 2", err: "Invalid assignment target."},
+		{source: "a + b += 1", err: "Invalid assignment target."},
+		{source: "5++", err: "Invalid assignment target."},
+		{source: "++(a)", err: "Invalid assignment target."},
+		{source: "++[a]", err: "Invalid assignment target."},
+	})
+}
+
+func TestListsAndSlices(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: "[1, 2, 3]", tree: "(list 1 2 3)"},
+		{source: "[]", tree: "(list)"},
+		{source: "[1, [2],]", tree: "(list 1 (list 2))"},
+		{source: "xs[1][2]", tree: "(index (index xs 1) 2)"},
+		{source: "xs[1:2]", tree: "(slice xs 1 2)"},
+		{source: "xs[:2]", tree: "(slice xs null 2)"},
+		{source: "xs[1:]", tree: "(slice xs 1 null)"},
+		{source: "xs[:]", tree: "(slice xs null null)"},
+		{source: "[1, 2", err: "Expected ']' after list elements."},
+	})
+}
+
+func TestMapLiterals(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: `{"a": 1, b: 2}`, tree: "(map a 1 b 2)"},
+		{source: "{}", tree: "(map)"},
+		{source: "{1: {2: 3},}", tree: "(map 1 (map 2 3))"},
+		{source: `m["a"]`, tree: "(index m a)"},
+		{source: "{1: 2", err: "Expected '}' after map entries."},
+	})
+	parseStatements(t, []statementTest{
+		{source: "{ print 1; }"},
+		{source: "{}"},
+		{source: `var m = {"a": 1};`},
+		{source: `{"a": 1};`},
+	})
+}
+
+func TestForIn(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: "for (x in xs) print x;"},
+		{source: "for (k, v in m) { print k; }"},
+		{source: "for (i in 0..10) print i;"},
+		{source: "for x in xs {}", err: "Expected '(' after 'for'."},
+		{source: "for (x xs) {}", err: "Expected 'in' after loop variable."},
+		{source: "for (x, in xs) {}", err: "Expected loop variable name after ','."},
+		{source: "for (x in xs {}", err: "Expected ')' after for clause."},
+	})
+}
+
+func TestLoopControl(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: "while (true) break;"},
+		{source: "for (x in xs) { if (x) continue; }"},
+		{source: "outer: while (true) { for (x in xs) break outer; }"},
+		{source: "outer: for (x in xs) { inner: while (true) continue outer; }"},
+		{source: "a: while (true) {} a: while (true) {}"},
+		{source: "break;", err: "Can't use 'break' outside of a loop."},
+		{source: "if (true) { continue; }", err: "Can't use 'continue' outside of a loop."},
+		{source: "while (true) { fn f() { break; } }", err: "Can't use 'break' outside of a loop."},
+		{source: "while (true) break outer;", err: "Undefined loop label 'outer'."},
+		{source: "outer: while (true) {} while (true) break outer;", err: "Undefined loop label 'outer'."},
+		{source: "a: while (true) a: while (true) {}", err: "Duplicate loop label 'a'."},
+		{source: "a: print 1;", err: "Expected loop after label."},
+		{source: "while (true) break", err: "Expected ';' after 'break'."},
+	})
+}
+
+func TestExceptions(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: "throw 1;"},
+		{source: "try { print 1; } catch (e) { print e; }"},
+		{source: "try { print 1; } finally { print 2; }"},
+		{source: "try {} catch (e) {} finally {}"},
+		{source: "throw 1", err: "Expected ';' after thrown value."},
+		{source: "try {}", err: "Expected 'catch' or 'finally' after try block."},
+		{source: "try print 1;", err: "Expected '{' after 'try'."},
+		{source: "try {} catch e {}", err: "Expected '(' after 'catch'."},
+		{source: "try {} catch () {}", err: "Expected error variable name."},
+		{source: "try {} catch (e {}", err: "Expected ')' after error variable name."},
+		{source: "try {} finally print 1;", err: "Expected '{' after 'finally'."},
+	})
+}
+
+func TestMatchPatterns(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: `match (x) { 1 => "one", "a" | "b" => "ab", _ => "other" }`, tree: "(match x (1 => one) ((| a b) => ab) (_ => other))"},
+		{source: "match (x) { [a, b] => a, {name: n} => n, y if y > 1 => y }", tree: "(match x ((list a b) => a) ((map name n) => n) (y if (> y 1) => y))"},
+		{source: "match (x) { [a, ...rest] => a }", tree: "(match x ((list a ...rest) => a))"},
+		{source: "match (x) { -1 => 0 }", tree: "(match x ((- 1) => 0))"},
+		{source: "match (x) { 1 => 2", err: "Expected '}' after match arms."},
+		{source: "match (x) { 1 2 }", err: "Expected '=>' after match pattern."},
+		{source: "match (x) { + => 2 }", err: "Expected pattern."},
+		{source: "match (x) { - a => 2 }", err: "Expected number after '-' in pattern."},
+		{source: "match (x) { [...] => 2 }", err: "Expected variable name after '...'."},
+	})
+}
+
+func TestMatchExhaustive(t *testing.T) {
+	tests := []struct {
+		source  string
+		warning string
+	}{
+		{source: "match (x) { true => 1, false => 2, nil => 3 }"},
+		{source: "match (x) { true | false | nil => 1 }"},
+		{source: "match (x) { true => 1, _ => 2 }"},
+		{source: "match (x) { true => 1, y => 2 }"},
+		{source: "match (x) { 1 => 1, 2 => 2 }"},
+		{source: "match (x) { nil => 1, 2 => 2 }"},
+		{source: "match (x) { true => 1, false => 2 }", warning: "Match is not exhaustive, missing nil."},
+		{source: "match (x) { true | false => 1 }", warning: "Match is not exhaustive, missing nil."},
+		{source: "match (x) { true => 1 }", warning: "Match is not exhaustive, missing false and nil."},
+		{source: "match (x) { false => 1, nil => 2 }", warning: "Match is not exhaustive, missing true."},
+		// Other literals and destructuring patterns don't cover the missing values
+		{source: `match (x) { true => 1, "a" => 2 }`, warning: "Match is not exhaustive, missing false and nil."},
+		{source: "match (x) { true => 1, [a] => a, {k: v} => v }", warning: "Match is not exhaustive, missing false and nil."},
+		{source: "match (x) { false => 1, [_] | _ => 2 }"},
+		// Guarded arms may not match
+		{source: "match (x) { true => 1, false => 2, _ if x => 3 }", warning: "Match is not exhaustive, missing nil."},
+		{source: "match (x) { true => 1, false if y => 2, nil => 3 }", warning: "Match is not exhaustive, missing false."},
+		{source: "match (x) { [true] => 1 }"},
+	}
+	for _, test := range tests {
+		p := parserFor(t, test.source)
+		if _, err := p.expression(); err != nil {
+			t.Errorf("%q: unexpected error %q", test.source, err)
+			continue
+		}
+		warning := ""
+		if len(p.Warnings) > 0 {
+			warning = p.Warnings[0].Error()
+		}
+		if len(p.Warnings) > 1 || warning != test.warning {
+			t.Errorf("%q: got warnings %v, want %q", test.source, p.Warnings, test.warning)
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: "var [a, b, ...rest] = xs;"},
+		{source: "var {name, age} = person;"},
+		{source: `var {"first name": first, nested: [x, _]} = person;`},
+		{source: "var [] = xs;"},
+		{source: "[a, b] = [b, a];"},
+		{source: "[a, [b, c]] = xs;"},
+		{source: "var [a, b] xs;", err: "Expected '=' after destructuring pattern."},
+		{source: "var [1] = xs;", err: "Expected variable name or destructuring pattern."},
+		{source: "var [a | b] = xs;", err: "Expected ']' after list pattern."},
+		{source: "var [...] = xs;", err: "Expected variable name after '...'."},
+		{source: "var {1} = m;", err: "Expected ':' after map pattern key."},
+		{source: "var {[a]: b} = m;", err: "Expected key in map pattern."},
+		{source: "var [a] = xs", err: "Expected ';' after variable declaration."},
+		{source: "[a, 1] = xs;", err: "Invalid assignment target."},
+	})
+}
+
+func TestFunctionSignatures(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: `fn greet(name, greeting = "hi") { print greeting; }`},
+		{source: "fn sum(...nums) { return nums; }"},
+		{source: "fn f(a, b = a + 1, ...rest) {}"},
+		{source: `greet(name: "x");`},
+		{source: `greet("x", greeting: "yo");`},
+		{source: "fn f(a = 1, b) {}", err: "Parameter without a default can't follow parameters with defaults."},
+		{source: "fn f(...rest, a) {}", err: "Expected ')' after parameters."},
+		{source: "fn f(...) {}", err: "Expected parameter name after '...'."},
+		{source: "fn f(1) {}", err: "Expected parameter name."},
+		{source: `greet(name: "x", "y");`, err: "Positional arguments can't follow named arguments."},
+		{source: "greet(1, 2;", err: "Expected ')' after arguments."},
+	})
+}
+
+func TestConstDeclarations(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: "const x = 1;"},
+		{source: "const xs = freeze([1, 2]);"},
+		{source: "const x;", err: "Expected '=' after constant name."},
+		{source: "const = 1;", err: "Expected constant name."},
+		{source: "const x = 1", err: "Expected ';' after constant declaration."},
+	})
+}
+
+func TestMethodCalls(t *testing.T) {
+	parseExpressions(t, []expressionTest{
+		{source: `"a".upper()`, tree: "(call (.upper a))"},
+		{source: `s.replace("a", "b", 1).len()`, tree: "(call (.len (call (.replace s) a b 1)))"},
+		// Keywords can name methods
+		{source: "r.match(s)", tree: "(call (.match r) s)"},
+		{source: "s.", err: "Expected property name after '.'."},
+	})
+}
+
+func TestPrintOptions(t *testing.T) {
+	parseStatements(t, []statementTest{
+		{source: "print;"},
+		{source: "print 1, 2;"},
+		{source: `print 1, 2, sep: ", ", end: "";`},
+		{source: `eprint "oops", end: "!";`},
+		{source: `print sep: "-";`},
+		{source: `print 1, sep: "-", sep: "+";`, err: "Unknown or repeated print option 'sep'."},
+		{source: `print 1, color: "red";`, err: "Unknown or repeated print option 'color'."},
+		{source: `print sep: "-", 1;`, err: "Values can't follow print options."},
+		{source: "print 1", err: "Expected ';' after value."},
+	})
+}
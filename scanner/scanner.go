@@ -2,30 +2,12 @@ package scanner
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/levi/holo/token"
 )
 
-var keywords = map[string]string{
-	"and":    token.AndToken,
-	"class":  token.ClassToken,
-	"else":   token.ElseToken,
-	"false":  token.FalseToken,
-	"for":    token.ForToken,
-	"fn":     token.FnToken,
-	"if":     token.IfToken,
-	"nil":    token.NilToken,
-	"or":     token.OrToken,
-	"print":  token.PrintToken,
-	"return": token.ReturnToken,
-	"self":   token.SelfToken,
-	"super":  token.SuperToken,
-	"true":   token.TrueToken,
-	"var":    token.VarToken,
-	"while":  token.WhileToken,
-}
-
 // Scanner scans a source file for tokens
 type Scanner struct {
 	Source string
@@ -69,18 +51,70 @@ func (s *Scanner) scanToken() {
 		s.addToken(token.LeftBraceToken)
 	case "}":
 		s.addToken(token.RightBraceToken)
+	case "[":
+		s.addToken(token.LeftBracketToken)
+	case "]":
+		s.addToken(token.RightBracketToken)
 	case ",":
 		s.addToken(token.CommaToken)
 	case ".":
-		s.addToken(token.DotToken)
+		if s.match(".") {
+			if s.match(".") {
+				s.addToken(token.DotDotDotToken)
+			} else {
+				s.addToken(token.DotDotToken)
+			}
+		} else {
+			s.addToken(token.DotToken)
+		}
 	case "-":
-		s.addToken(token.MinusToken)
+		if s.match("-") {
+			s.addToken(token.MinusMinusToken)
+		} else if s.match("=") {
+			s.addToken(token.MinusEqualToken)
+		} else {
+			s.addToken(token.MinusToken)
+		}
 	case "+":
-		s.addToken(token.PlusToken)
+		if s.match("+") {
+			s.addToken(token.PlusPlusToken)
+		} else if s.match("=") {
+			s.addToken(token.PlusEqualToken)
+		} else {
+			s.addToken(token.PlusToken)
+		}
 	case ";":
 		s.addToken(token.SemicolonToken)
 	case "*":
-		s.addToken(token.StarToken)
+		if s.match("*") {
+			s.addToken(token.StarStarToken)
+		} else if s.match("=") {
+			s.addToken(token.StarEqualToken)
+		} else {
+			s.addToken(token.StarToken)
+		}
+	case "%":
+		if s.match("=") {
+			s.addToken(token.PercentEqualToken)
+		} else {
+			s.addToken(token.PercentToken)
+		}
+	case "&":
+		s.addToken(token.AmpersandToken)
+	case "|":
+		s.addToken(token.PipeToken)
+	case "^":
+		s.addToken(token.CaretToken)
+	case "~":
+		s.addToken(token.TildeToken)
+	case ":":
+		s.addToken(token.ColonToken)
+	case "?":
+		if s.match("?") {
+			s.addToken(token.QuestionQuestionToken)
+		} else {
+			s.addToken(token.QuestionToken)
+		}
 	case "!":
 		if s.match("=") {
 			s.addToken(token.BangEqualToken)
@@ -90,29 +124,40 @@ func (s *Scanner) scanToken() {
 	case "=":
 		if s.match("=") {
 			s.addToken(token.EqualEqualToken)
+		} else if s.match(">") {
+			s.addToken(token.FatArrowToken)
 		} else {
 			s.addToken(token.EqualToken)
 		}
 	case "<":
 		if s.match("=") {
 			s.addToken(token.LessEqualToken)
+		} else if s.match("<") {
+			s.addToken(token.LessLessToken)
 		} else {
 			s.addToken(token.LessToken)
 		}
 	case ">":
 		if s.match("=") {
 			s.addToken(token.GreaterEqualToken)
+		} else if s.match(">") {
+			s.addToken(token.GreaterGreaterToken)
 		} else {
 			s.addToken(token.GreaterToken)
 		}
 	case "/":
 		if s.match("/") {
-			for s.peek() != "\n" && !s.isAtEnd() {
-				s.advance()
-			}
+			s.addToken(token.SlashSlashToken)
+		} else if s.match("=") {
+			s.addToken(token.SlashEqualToken)
 		} else {
 			s.addToken(token.SlashToken)
 		}
+	case "#":
+		// Comments run to the end of the line, as // is integer division
+		for s.peek() != "\n" && !s.isAtEnd() {
+			s.advance()
+		}
 	case "\"":
 		s.string()
 	case " ":
@@ -159,14 +204,56 @@ func (s *Scanner) number() {
 		s.advance()
 	}
 
+	fractional := false
 	if s.peek() == "." && isDigit(s.peekNext()) {
+		fractional = true
 		s.advance() // consume the .
 		for isDigit(s.peek()) {
 			s.advance()
 		}
 	}
 
-	n, err := strconv.ParseFloat(s.Source[s.start:s.cursor], 64)
+	digits := s.Source[s.start:s.cursor]
+
+	// An n suffix marks a bigint literal and a d suffix marks a decimal literal
+	if s.hasSuffix("n") {
+		s.advance()
+		if fractional {
+			s.raiseError("Bigint literal cannot have a fractional part")
+			return
+		}
+		n, ok := new(big.Int).SetString(digits, 10)
+		if !ok {
+			s.raiseError("Failed to parse bigint literal")
+			return
+		}
+		s.addTokenLiteral(token.NumberToken, n)
+		return
+	}
+
+	if s.hasSuffix("d") {
+		s.advance()
+		n, ok := new(big.Rat).SetString(digits)
+		if !ok {
+			s.raiseError("Failed to parse decimal literal")
+			return
+		}
+		s.addTokenLiteral(token.NumberToken, n)
+		return
+	}
+
+	// Literals without a fractional part are integers
+	if !fractional {
+		n, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			s.raiseError("Integer literal out of range")
+			return
+		}
+		s.addTokenLiteral(token.NumberToken, n)
+		return
+	}
+
+	n, err := strconv.ParseFloat(digits, 64)
 	if err != nil {
 		s.raiseError("Failed to parse number literal")
 		return
@@ -175,13 +262,18 @@ func (s *Scanner) number() {
 	s.addTokenLiteral(token.NumberToken, n)
 }
 
+// hasSuffix determines if the current character is a number literal suffix not followed by an identifier
+func (s *Scanner) hasSuffix(suffix string) bool {
+	return s.peek() == suffix && !isAlphaNumeric(s.peekNext())
+}
+
 func (s *Scanner) identifier() {
 	for isAlphaNumeric(s.peek()) {
 		s.advance()
 	}
 
 	value := s.Source[s.start:s.cursor]
-	if t, ok := keywords[value]; ok {
+	if t, ok := token.Keywords[value]; ok {
 		s.addToken(t)
 	} else {
 		s.addToken(token.IdentifierToken)
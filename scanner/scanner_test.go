@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/levi/holo/token"
+)
+
+// scanTest expects source to scan to tokens of types, ignoring the trailing EOF
+type scanTest struct {
+	source string
+	types  []string
+}
+
+func scanTypes(t *testing.T, tests []scanTest) {
+	t.Helper()
+	for _, test := range tests {
+		s := NewScanner(test.source)
+		tokens := s.ScanTokens()
+		if len(s.Errors) > 0 {
+			t.Errorf("%q: unexpected error %q", test.source, s.Errors[0].Error())
+			continue
+		}
+		types := []string{}
+		for _, tok := range tokens[:len(tokens)-1] {
+			types = append(types, tok.TokenType)
+		}
+		if fmt.Sprint(types) != fmt.Sprint(test.types) {
+			t.Errorf("%q: got %v, want %v", test.source, types, test.types)
+		}
+	}
+}
+
+// literalTest expects source to scan to a single literal token, or to an error
+type literalTest struct {
+	source  string
+	literal string
+	kind    string
+	err     string
+}
+
+func scanLiterals(t *testing.T, tests []literalTest) {
+	t.Helper()
+	for _, test := range tests {
+		s := NewScanner(test.source)
+		tokens := s.ScanTokens()
+		if test.err != "" {
+			if len(s.Errors) == 0 || s.Errors[0].Error() != test.err {
+				t.Errorf("%q: got errors %v, want %q", test.source, s.Errors, test.err)
+			}
+			continue
+		}
+		if len(s.Errors) > 0 {
+			t.Errorf("%q: unexpected error %q", test.source, s.Errors[0].Error())
+			continue
+		}
+		literal := tokens[0].Literal
+		if kind := fmt.Sprintf("%T", literal); kind != test.kind {
+			t.Errorf("%q: got %s literal, want %s", test.source, kind, test.kind)
+		}
+		if s := fmt.Sprint(literal); s != test.literal {
+			t.Errorf("%q: got literal %s, want %s", test.source, s, test.literal)
+		}
+	}
+}
+
+func TestIntegerLiterals(t *testing.T) {
+	scanLiterals(t, []literalTest{
+		{source: "0", literal: "0", kind: "int64"},
+		{source: "42", literal: "42", kind: "int64"},
+		{source: "9007199254740993", literal: "9007199254740993", kind: "int64"},
+		{source: "9223372036854775807", literal: "9223372036854775807", kind: "int64"},
+		{source: "9223372036854775808", err: "Integer literal out of range"},
+		{source: "1.5", literal: "1.5", kind: "float64"},
+		{source: "2.0", literal: "2", kind: "float64"},
+	})
+	scanTypes(t, []scanTest{
+		{"1.", []string{token.NumberToken, token.DotToken}},
+		{"-7", []string{token.MinusToken, token.NumberToken}},
+	})
+}
+
+func TestBigLiterals(t *testing.T) {
+	scanLiterals(t, []literalTest{
+		{source: "123n", literal: "123", kind: "*big.Int"},
+		{source: "99999999999999999999999n", literal: "99999999999999999999999", kind: "*big.Int"},
+		{source: "19.99d", literal: "1999/100", kind: "*big.Rat"},
+		{source: "3d", literal: "3/1", kind: "*big.Rat"},
+		{source: "1.5n", err: "Bigint literal cannot have a fractional part"},
+	})
+	scanTypes(t, []scanTest{
+		{"12nx", []string{token.NumberToken, token.IdentifierToken}},
+		{"1 d", []string{token.NumberToken, token.IdentifierToken}},
+	})
+}
+
+func TestOperators(t *testing.T) {
+	scanTypes(t, []scanTest{
+		{"% ** * //", []string{token.PercentToken, token.StarStarToken, token.StarToken, token.SlashSlashToken}},
+		{"/ & | ^ ~", []string{token.SlashToken, token.AmpersandToken, token.PipeToken, token.CaretToken, token.TildeToken}},
+		{"<< >> <= >=", []string{token.LessLessToken, token.GreaterGreaterToken, token.LessEqualToken, token.GreaterEqualToken}},
+		{"1 # a comment // with slashes\n2", []string{token.NumberToken, token.NumberToken}},
+		{"1//2", []string{token.NumberToken, token.SlashSlashToken, token.NumberToken}},
+	})
+}
+
+func TestAssignmentOperators(t *testing.T) {
+	scanTypes(t, []scanTest{
+		{"+= -= *= /= %=", []string{token.PlusEqualToken, token.MinusEqualToken, token.StarEqualToken, token.SlashEqualToken, token.PercentEqualToken}},
+		{"x++ --y", []string{token.IdentifierToken, token.PlusPlusToken, token.MinusMinusToken, token.IdentifierToken}},
+		{"a+ +b", []string{token.IdentifierToken, token.PlusToken, token.PlusToken, token.IdentifierToken}},
+	})
+}
+
+func TestLoopKeywords(t *testing.T) {
+	scanTypes(t, []scanTest{
+		{source: "break continue", types: []string{token.BreakToken, token.ContinueToken}},
+		{source: "outer: while", types: []string{token.IdentifierToken, token.ColonToken, token.WhileToken}},
+		{source: "breaking", types: []string{token.IdentifierToken}},
+	})
+}
+
+func TestConstKeyword(t *testing.T) {
+	scanTypes(t, []scanTest{
+		{source: "const x = 1;", types: []string{token.ConstToken, token.IdentifierToken, token.EqualToken, token.NumberToken, token.SemicolonToken}},
+		{source: "constant", types: []string{token.IdentifierToken}},
+	})
+}
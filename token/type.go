@@ -2,27 +2,51 @@ package token
 
 const (
 	// Single character tokens
-	LeftParenToken  = "LeftParen"
-	RightParenToken = "RightParen"
-	LeftBraceToken  = "LeftBrace"
-	RightBraceToken = "RightBrace"
-	CommaToken      = "Comma"
-	DotToken        = "Dot"
-	MinusToken      = "Minus"
-	PlusToken       = "Plus"
-	SemicolonToken  = "Semicolon"
-	SlashToken      = "Slash"
-	StarToken       = "Star"
+	LeftParenToken    = "LeftParen"
+	RightParenToken   = "RightParen"
+	LeftBraceToken    = "LeftBrace"
+	RightBraceToken   = "RightBrace"
+	LeftBracketToken  = "LeftBracket"
+	RightBracketToken = "RightBracket"
+	CommaToken        = "Comma"
+	DotToken          = "Dot"
+	MinusToken        = "Minus"
+	PlusToken         = "Plus"
+	SemicolonToken    = "Semicolon"
+	SlashToken        = "Slash"
+	StarToken         = "Star"
+	PercentToken      = "Percent"
+	AmpersandToken    = "Ampersand"
+	PipeToken         = "Pipe"
+	CaretToken        = "Caret"
+	TildeToken        = "Tilde"
+	ColonToken        = "Colon"
 
 	// One or two character tokens
-	BangToken         = "Bang"
-	BangEqualToken    = "BangEqual"
-	EqualToken        = "Equal"
-	EqualEqualToken   = "EqualEqual"
-	GreaterToken      = "Greater"
-	GreaterEqualToken = "GreaterEqual"
-	LessToken         = "Less"
-	LessEqualToken    = "LessEqual"
+	BangToken             = "Bang"
+	BangEqualToken        = "BangEqual"
+	EqualToken            = "Equal"
+	EqualEqualToken       = "EqualEqual"
+	GreaterToken          = "Greater"
+	GreaterEqualToken     = "GreaterEqual"
+	LessToken             = "Less"
+	LessEqualToken        = "LessEqual"
+	LessLessToken         = "LessLess"
+	GreaterGreaterToken   = "GreaterGreater"
+	SlashSlashToken       = "SlashSlash"
+	StarStarToken         = "StarStar"
+	DotDotToken           = "DotDot"
+	DotDotDotToken        = "DotDotDot"
+	FatArrowToken         = "FatArrow"
+	QuestionToken         = "Question"
+	QuestionQuestionToken = "QuestionQuestion"
+	PlusEqualToken        = "PlusEqual"
+	MinusEqualToken       = "MinusEqual"
+	StarEqualToken        = "StarEqual"
+	SlashEqualToken       = "SlashEqual"
+	PercentEqualToken     = "PercentEqual"
+	PlusPlusToken         = "PlusPlus"
+	MinusMinusToken       = "MinusMinus"
 
 	// Literals
 	IdentifierToken = "Identifier"
@@ -30,22 +54,82 @@ const (
 	NumberToken     = "Number"
 
 	// Keywords
-	AndToken    = "And"
-	ClassToken  = "Class"
-	ElseToken   = "Else"
-	FalseToken  = "False"
-	FnToken     = "Fn"
-	ForToken    = "For"
-	IfToken     = "If"
-	NilToken    = "Nil"
-	OrToken     = "Or"
-	PrintToken  = "Print"
-	ReturnToken = "Return"
-	SelfToken   = "Self"
-	SuperToken  = "Super"
-	TrueToken   = "True"
-	VarToken    = "Var"
-	WhileToken  = "While"
+	AndToken      = "And"
+	AsToken       = "As"
+	BreakToken    = "Break"
+	CatchToken    = "Catch"
+	ClassToken    = "Class"
+	ConstToken    = "Const"
+	ContinueToken = "Continue"
+	ElseToken     = "Else"
+	EprintToken   = "Eprint"
+	ExportToken   = "Export"
+	FalseToken    = "False"
+	FinallyToken  = "Finally"
+	FnToken       = "Fn"
+	ForToken      = "For"
+	IfToken       = "If"
+	ImportToken   = "Import"
+	InToken       = "In"
+	MatchToken    = "Match"
+	NilToken      = "Nil"
+	OrToken       = "Or"
+	PrintToken    = "Print"
+	ReturnToken   = "Return"
+	SelfToken     = "Self"
+	SuperToken    = "Super"
+	ThrowToken    = "Throw"
+	TrueToken     = "True"
+	TryToken      = "Try"
+	VarToken      = "Var"
+	WhileToken    = "While"
 
 	EOFToken = "EOF"
 )
+
+// Keywords maps reserved words to their token types
+var Keywords = map[string]string{
+	"and":      AndToken,
+	"as":       AsToken,
+	"break":    BreakToken,
+	"catch":    CatchToken,
+	"class":    ClassToken,
+	"const":    ConstToken,
+	"continue": ContinueToken,
+	"else":     ElseToken,
+	"eprint":   EprintToken,
+	"export":   ExportToken,
+	"false":    FalseToken,
+	"finally":  FinallyToken,
+	"for":      ForToken,
+	"fn":       FnToken,
+	"if":       IfToken,
+	"import":   ImportToken,
+	"in":       InToken,
+	"match":    MatchToken,
+	"nil":      NilToken,
+	"or":       OrToken,
+	"print":    PrintToken,
+	"return":   ReturnToken,
+	"self":     SelfToken,
+	"super":    SuperToken,
+	"throw":    ThrowToken,
+	"true":     TrueToken,
+	"try":      TryToken,
+	"var":      VarToken,
+	"while":    WhileToken,
+}
+
+// keywordTypes holds the token types of keywords
+var keywordTypes = map[string]bool{}
+
+func init() {
+	for _, tokenType := range Keywords {
+		keywordTypes[tokenType] = true
+	}
+}
+
+// IsKeyword reports whether a token type is a keyword
+func IsKeyword(tokenType string) bool {
+	return keywordTypes[tokenType]
+}
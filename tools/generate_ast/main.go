@@ -16,10 +16,38 @@ func main() {
 
 	outputDir := os.Args[1]
 	err := defineAst(outputDir, "Expr", []string{
+		"Assign		: name token.Token, operation token.Token, value Expr",
+		"AssignList	: bracket token.Token, targets []Expr, value Expr",
 		"Binary		: left Expr, operation token.Token, right Expr",
+		"Call		: callee Expr, paren token.Token, arguments []Expr, names []string",
+		"Conditional	: condition Expr, thenBranch Expr, elseBranch Expr",
+		"Get		: object Expr, name token.Token",
 		"Grouping	: expression Expr",
+		"Increment	: target Expr, operation token.Token, prefix bool",
+		"Index		: object Expr, bracket token.Token, index Expr",
+		"IndexSet	: object Expr, bracket token.Token, index Expr, operation token.Token, value Expr",
+		"ListLiteral	: bracket token.Token, elements []Expr",
 		"Literal	: value interface{}",
+		"Logical	: left Expr, operation token.Token, right Expr",
+		"MapLiteral	: brace token.Token, keys []Expr, values []Expr",
+		"Match		: keyword token.Token, subject Expr, patterns []Pattern, guards []Expr, bodies []Expr",
+		"RangeLiteral	: start Expr, operation token.Token, end Expr",
+		"Slice		: object Expr, bracket token.Token, start Expr, end Expr",
 		"Unary		: operation token.Token, right Expr",
+		"Variable	: name token.Token",
+	})
+
+	if err != nil {
+		panic(err)
+	}
+
+	err = defineAst(outputDir, "Pattern", []string{
+		"AlternativePattern : alternatives []Pattern",
+		"BindingPattern     : name token.Token",
+		"ListPattern        : bracket token.Token, elements []Pattern, rest Pattern",
+		"LiteralPattern     : value Expr",
+		"MapPattern         : brace token.Token, keys []interface{}, values []Pattern",
+		"WildcardPattern    : name token.Token",
 	})
 
 	if err != nil {
@@ -27,8 +55,23 @@ func main() {
 	}
 
 	err = defineAst(outputDir, "Stmt", []string{
+		"Block      : statements []Stmt",
+		"Break      : keyword token.Token, label string",
+		"Const      : name token.Token, initializer Expr",
+		"Continue   : keyword token.Token, label string",
+		"Export     : keyword token.Token, declaration Stmt",
 		"Expression : expression Expr",
-		"Print      : expression Expr",
+		"Fn         : name token.Token, params []token.Token, defaults []Expr, variadic bool, body []Stmt",
+		"ForIn      : names []token.Token, keyword token.Token, iterable Expr, body Stmt, label string",
+		"If         : condition Expr, thenBranch Stmt, elseBranch Stmt",
+		"Import     : keyword token.Token, path string, name token.Token",
+		"Print      : keyword token.Token, expressions []Expr, sep Expr, end Expr",
+		"Return     : keyword token.Token, value Expr",
+		"Throw      : keyword token.Token, value Expr",
+		"Try        : body []Stmt, name token.Token, catchBody []Stmt, finallyBody []Stmt",
+		"Var        : name token.Token, initializer Expr",
+		"VarPattern : keyword token.Token, pattern Pattern, initializer Expr",
+		"While      : condition Expr, body Stmt, label string",
 	})
 
 	if err != nil {